@@ -0,0 +1,26 @@
+//go:build ignore
+
+// Run with: go run commands.go
+package main
+
+import (
+	"fmt"
+
+	"input/repl"
+)
+
+func main() {
+	r := repl.New("Type command: ")
+	r.Add(repl.Command{Name: "print", Args: "[file]", Help: "print a file", Run: func(args []string) error {
+		if len(args) == 0 {
+			r.Println("printing file")
+		} else {
+			r.Println("printing file", args[0])
+		}
+		return nil
+	}})
+	if err := r.Run(); err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println("program exit")
+}
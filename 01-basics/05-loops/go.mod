@@ -0,0 +1,13 @@
+module loops
+
+go 1.21
+
+require (
+	input v0.0.0
+	strings-example v0.0.0
+)
+
+replace (
+	input => ../06-user-input
+	strings-example => ../../05-misc/02-strings
+)
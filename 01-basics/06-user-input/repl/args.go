@@ -0,0 +1,78 @@
+package repl
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrUnclosedQuote is returned by Split for a line like `store "Jane Doe`.
+var ErrUnclosedQuote = errors.New("missing closing quote")
+
+// Split splits line into arguments at spaces, like a shell does. Quotes
+// keep spaces in an argument, "Jane Doe" or 'Jane Doe', and a backslash
+// takes the next character as it is, Jane\ Doe.
+func Split(line string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	inArg := false
+	var quote rune
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			arg.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inArg = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				arg.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 || escaped {
+		return args, ErrUnclosedQuote
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
+
+// Quote returns s the way Split reads it back as one argument.
+func Quote(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\"'\\") {
+		return s
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// argCount returns how many arguments a usage like "<file> [name...]"
+// allows. Arguments in <> are required, in [] optional, and one ending
+// in ... can be repeated. max is -1 for no limit.
+func argCount(usage string) (min, max int) {
+	for _, arg := range strings.Fields(usage) {
+		if strings.HasSuffix(strings.TrimRight(arg, ">]"), "...") {
+			max = -1
+		} else if max >= 0 {
+			max++
+		}
+		if strings.HasPrefix(arg, "<") {
+			min++
+		}
+	}
+	return min, max
+}
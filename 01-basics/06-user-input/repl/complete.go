@@ -0,0 +1,84 @@
+package repl
+
+import (
+	"sort"
+	"strings"
+)
+
+// Complete returns what the last word of line can be completed to: a
+// command name for the first word, otherwise what the command's Complete
+// function suggests. Candidates that need quotes are quoted.
+func (r *REPL) Complete(line string) []string {
+	args, err := Split(line)
+	if err != nil {
+		// complete inside an open quote as if it was closed
+		line = closeQuote(line)
+		args, _ = Split(line)
+	}
+	word := ""
+	if len(args) > 0 && !strings.HasSuffix(line, " ") {
+		word = args[len(args)-1]
+		args = args[:len(args)-1]
+	}
+
+	var options []string
+	if len(args) == 0 {
+		options = r.names()
+	} else if c, ok := r.commands[args[0]]; ok && c.Complete != nil {
+		options = c.Complete(args[1:])
+	}
+
+	var candidates []string
+	for _, o := range options {
+		if strings.HasPrefix(strings.ToLower(o), strings.ToLower(word)) {
+			candidates = append(candidates, Quote(o))
+		}
+	}
+	sort.Strings(candidates)
+	return candidates
+}
+
+// closeQuote returns line with the quote that's open at its end closed,
+// ' or ", and a backslash at the end left out.
+func closeQuote(line string) string {
+	var quote rune
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		}
+	}
+	if escaped {
+		line = line[:len(line)-1]
+	}
+	if quote != 0 {
+		line += string(quote)
+	}
+	return line
+}
+
+// commonPrefix returns the longest prefix all of list start with.
+func commonPrefix(list []string) string {
+	if len(list) == 0 {
+		return ""
+	}
+	prefix := []rune(list[0])
+	for _, s := range list[1:] {
+		runes := []rune(s)
+		n := 0
+		for n < len(prefix) && n < len(runes) && prefix[n] == runes[n] {
+			n++
+		}
+		prefix = prefix[:n]
+	}
+	return string(prefix)
+}
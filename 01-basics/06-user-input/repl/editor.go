@@ -0,0 +1,229 @@
+package repl
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"strings-example/textutil"
+)
+
+// errInterrupted is returned by ReadLine when the user presses Ctrl-C.
+var errInterrupted = errors.New("interrupted")
+
+const (
+	keyCtrlA     = 1
+	keyCtrlC     = 3
+	keyCtrlD     = 4
+	keyCtrlE     = 5
+	keyBackspace = 8
+	keyTab       = 9
+	keyEnter     = 13
+	keyCtrlU     = 21
+	keyEscape    = 27
+	keyDelete    = 127
+)
+
+// editor reads lines from a terminal in raw mode, so it sees every key:
+// tab completes, the arrow keys move and go through history.
+type editor struct {
+	in  *bufio.Reader
+	out io.Writer
+	// raw puts the terminal in raw mode and returns how to undo it. It's
+	// nil when reading keys from something other than a terminal.
+	raw func() (restore func(), err error)
+
+	prompt string
+	buf    []rune
+	pos    int
+}
+
+func newEditor(f *os.File, out io.Writer) (*editor, error) {
+	raw := func() (func(), error) { return makeRaw(int(f.Fd())) }
+	restore, err := raw()
+	if err != nil {
+		return nil, err
+	}
+	restore()
+	return &editor{in: bufio.NewReader(f), out: out, raw: raw}, nil
+}
+
+func (e *editor) Interactive() bool {
+	return true
+}
+
+func (e *editor) ReadLine(prompt string, r *REPL) (string, error) {
+	if e.raw != nil {
+		restore, err := e.raw()
+		if err != nil {
+			return "", err
+		}
+		defer restore()
+	}
+
+	var history []string
+	if r != nil {
+		history = r.history
+	}
+	e.prompt, e.buf, e.pos = prompt, nil, 0
+	current := len(history) // the history line shown, len(history) is the new line
+	var typed []rune        // the new line, while going through history
+	e.redraw()
+
+	for {
+		key, _, err := e.in.ReadRune()
+		if err != nil {
+			return "", io.EOF
+		}
+		switch key {
+		case keyEnter, '\n':
+			fmt.Fprintln(e.out)
+			return string(e.buf), nil
+		case keyCtrlC:
+			fmt.Fprintln(e.out, "^C")
+			return "", errInterrupted
+		case keyCtrlD:
+			if len(e.buf) == 0 {
+				return "", io.EOF
+			}
+			e.deleteAt(e.pos)
+		case keyDelete, keyBackspace:
+			if e.pos > 0 {
+				e.pos--
+				e.deleteAt(e.pos)
+			}
+		case keyCtrlA:
+			e.pos = 0
+		case keyCtrlE:
+			e.pos = len(e.buf)
+		case keyCtrlU:
+			e.buf, e.pos = e.buf[e.pos:], 0
+		case keyTab:
+			if r != nil {
+				e.complete(r)
+			}
+		case keyEscape:
+			switch e.escape() {
+			case 'A': // up
+				if current > 0 {
+					if current == len(history) {
+						typed = e.buf
+					}
+					current--
+					e.buf = []rune(history[current])
+					e.pos = len(e.buf)
+				}
+			case 'B': // down
+				if current < len(history) {
+					current++
+					if current == len(history) {
+						e.buf = typed
+					} else {
+						e.buf = []rune(history[current])
+					}
+					e.pos = len(e.buf)
+				}
+			case 'C': // right
+				e.pos = min(e.pos+1, len(e.buf))
+			case 'D': // left
+				e.pos = max(e.pos-1, 0)
+			case 'H':
+				e.pos = 0
+			case 'F':
+				e.pos = len(e.buf)
+			case '3': // delete
+				e.deleteAt(e.pos)
+			}
+		default:
+			if key < ' ' {
+				continue
+			}
+			e.insert(string(key))
+		}
+		e.redraw()
+	}
+}
+
+// escape reads the rest of an escape sequence, like "[A" for the up arrow,
+// and returns its last character, or '3' for delete, "[3~".
+func (e *editor) escape() rune {
+	if r, _, _ := e.in.ReadRune(); r != '[' && r != 'O' {
+		return 0
+	}
+	r, _, _ := e.in.ReadRune()
+	if r >= '0' && r <= '9' {
+		for next, _, err := e.in.ReadRune(); err == nil && next != '~'; next, _, err = e.in.ReadRune() {
+		}
+	}
+	return r
+}
+
+func (e *editor) insert(s string) {
+	runes := []rune(s)
+	e.buf = append(e.buf[:e.pos], append(runes, e.buf[e.pos:]...)...)
+	e.pos += len(runes)
+}
+
+func (e *editor) deleteAt(i int) {
+	if i < len(e.buf) {
+		e.buf = append(e.buf[:i:i], e.buf[i+1:]...)
+	}
+}
+
+// complete completes the word before the cursor. One candidate is filled
+// in. More fill in what they have in common, or are listed if that's nothing.
+func (e *editor) complete(r *REPL) {
+	line := string(e.buf[:e.pos])
+	candidates := r.Complete(line)
+	if len(candidates) == 0 {
+		return
+	}
+	start := wordStart(e.buf[:e.pos])
+	word := string(e.buf[start:e.pos])
+
+	replacement := commonPrefix(candidates)
+	if len(candidates) == 1 {
+		replacement += " "
+	}
+	if len([]rune(replacement)) > len([]rune(word)) {
+		e.buf = append(e.buf[:start:start], e.buf[e.pos:]...)
+		e.pos = start
+		e.insert(replacement)
+		return
+	}
+	fmt.Fprintf(e.out, "\n%s\n", strings.Join(candidates, "  "))
+}
+
+// wordStart returns where the last word in line starts, taking quotes
+// and escaped spaces into account.
+func wordStart(line []rune) int {
+	start := 0
+	var quote rune
+	for i := 0; i < len(line); i++ {
+		switch r := line[i]; {
+		case r == '\\' && quote != '\'':
+			i++
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == ' ' || r == '\t':
+			start = i + 1
+		}
+	}
+	return start
+}
+
+// redraw shows the prompt and the line, with the cursor where it is.
+func (e *editor) redraw() {
+	s := "\r\033[K" + e.prompt + string(e.buf)
+	if back := textutil.Width(string(e.buf[e.pos:])); back > 0 {
+		s += fmt.Sprintf("\033[%dD", back)
+	}
+	fmt.Fprint(e.out, s)
+}
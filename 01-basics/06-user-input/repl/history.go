@@ -0,0 +1,49 @@
+package repl
+
+import (
+	"os"
+	"strings"
+)
+
+// History returns the lines entered so far, oldest first, including the
+// ones loaded from HistoryFile.
+func (r *REPL) History() []string {
+	return r.history
+}
+
+func (r *REPL) addHistory(line string) {
+	if line == "" || len(r.history) > 0 && r.history[len(r.history)-1] == line {
+		return
+	}
+	r.history = append(r.history, line)
+}
+
+func (r *REPL) loadHistory() {
+	if r.HistoryFile == "" {
+		return
+	}
+	data, err := os.ReadFile(r.HistoryFile)
+	if err != nil {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+		r.addHistory(line)
+	}
+	r.loaded = len(r.history)
+}
+
+// saveHistory writes the last HistorySize lines, if anything was added.
+func (r *REPL) saveHistory() error {
+	if r.HistoryFile == "" || len(r.history) == r.loaded {
+		return nil
+	}
+	history := r.history
+	size := r.HistorySize
+	if size == 0 {
+		size = DefaultHistorySize
+	}
+	if len(history) > size {
+		history = history[len(history)-size:]
+	}
+	return os.WriteFile(r.HistoryFile, []byte(strings.Join(history, "\n")+"\n"), 0600)
+}
@@ -0,0 +1,347 @@
+// Package repl runs command line loops, the "Command> " kind: register
+// commands with their arguments and help, and the REPL reads lines,
+// splits them into arguments with quotes, runs the commands, keeps a
+// history, completes with tab and suggests commands for typos. It runs
+// scripts too, from a file or piped to stdin.
+package repl
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"strings-example/textutil"
+)
+
+// DefaultHistorySize is how many lines are saved when HistorySize is 0.
+const DefaultHistorySize = 500
+
+// ErrQuit stops the REPL. The quit command returns it, and so can yours.
+var ErrQuit = errors.New("quit")
+
+// Command is something the user can type.
+type Command struct {
+	Name string
+	// Args shows the arguments in help, and sets how many are allowed:
+	// "<file>" is required, "[name]" optional, "<text...>" one or more.
+	Args string
+	Help string
+	Run  func(args []string) error
+	// Complete returns what the next argument can be, given the ones
+	// typed before it. It's optional.
+	Complete func(args []string) []string
+}
+
+// UnknownCommandError is returned for a command that isn't registered.
+type UnknownCommandError struct {
+	Name        string
+	Suggestions []string
+}
+
+func (e *UnknownCommandError) Error() string {
+	if len(e.Suggestions) > 0 {
+		return fmt.Sprintf("unknown command %s, did you mean %s?", e.Name, strings.Join(e.Suggestions, " or "))
+	}
+	return fmt.Sprintf("unknown command %s, type help to see the commands", e.Name)
+}
+
+// UsageError is returned when a command gets too few or too many arguments.
+type UsageError struct {
+	Command Command
+}
+
+func (e *UsageError) Error() string {
+	return "usage: " + strings.TrimSpace(e.Command.Name+" "+e.Command.Args)
+}
+
+// ScriptError is returned by Run when a command in a script fails.
+type ScriptError struct {
+	Line int
+	Text string
+	Err  error
+}
+
+func (e *ScriptError) Error() string {
+	return fmt.Sprintf("line %d: %s: %v", e.Line, e.Text, e.Err)
+}
+
+func (e *ScriptError) Unwrap() error {
+	return e.Err
+}
+
+// REPL reads commands and runs them.
+type REPL struct {
+	Prompt string
+	In     io.Reader // os.Stdin if nil
+	Out    io.Writer // os.Stdout if nil
+
+	HistoryFile string // where history is kept between runs, if set
+	HistorySize int    // lines kept in HistoryFile, DefaultHistorySize if 0
+
+	// OnError shows an error from a command. By default it prints "Error: " and the error.
+	OnError func(err error)
+
+	commands map[string]Command
+	reader   lineReader
+	lineNo   int // lines read, for errors in scripts
+	history  []string
+	loaded   int
+}
+
+// New returns a REPL with the help, history and quit commands.
+func New(prompt string) *REPL {
+	r := &REPL{Prompt: prompt, commands: map[string]Command{}}
+	r.Add(Command{Name: "help", Args: "[command]", Help: "show the commands, or how to use one", Run: r.help, Complete: r.completeCommand})
+	r.Add(Command{Name: "history", Help: "list what you typed", Run: r.showHistory})
+	r.Add(Command{Name: "quit", Help: "leave", Run: func([]string) error { return ErrQuit }})
+	return r
+}
+
+// Add registers c, replacing a command with the same name.
+func (r *REPL) Add(c Command) {
+	r.commands[c.Name] = c
+}
+
+func (r *REPL) names() []string {
+	var names []string
+	for name := range r.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r *REPL) completeCommand(args []string) []string {
+	if len(args) > 0 {
+		return nil
+	}
+	return r.names()
+}
+
+func (r *REPL) out() io.Writer {
+	if r.Out == nil {
+		return os.Stdout
+	}
+	return r.Out
+}
+
+// Printf prints to the REPL's output.
+func (r *REPL) Printf(format string, a ...interface{}) {
+	fmt.Fprintf(r.out(), format, a...)
+}
+
+// Println prints to the REPL's output.
+func (r *REPL) Println(a ...interface{}) {
+	fmt.Fprintln(r.out(), a...)
+}
+
+// Exec runs one line. It returns ErrQuit when the line asks to quit.
+func (r *REPL) Exec(line string) error {
+	args, err := Split(line)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return nil
+	}
+	c, ok := r.commands[args[0]]
+	if !ok {
+		return &UnknownCommandError{Name: args[0], Suggestions: r.suggest(args[0])}
+	}
+	min, max := argCount(c.Args)
+	if n := len(args) - 1; n < min || max >= 0 && n > max {
+		return &UsageError{Command: c}
+	}
+	return c.Run(args[1:])
+}
+
+// suggest returns the commands name is closest to being a typo of, and
+// the ones it's the start of.
+func (r *REPL) suggest(name string) []string {
+	var suggestions []string
+	for _, s := range textutil.Closest(name, r.names(), 2) {
+		if len(suggestions) > 0 && textutil.TypoDistance(name, s) > textutil.TypoDistance(name, suggestions[0]) {
+			break
+		}
+		suggestions = append(suggestions, s)
+	}
+	for _, n := range r.names() {
+		if strings.HasPrefix(n, name) && !contains(suggestions, n) {
+			suggestions = append(suggestions, n)
+		}
+	}
+	return suggestions
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Ask prints question and returns the answer, read from the same input as
+// the commands. In a script it reads the next line without printing.
+func (r *REPL) Ask(question string) (string, error) {
+	if r.reader == nil {
+		r.reader = r.newReader()
+	}
+	answer, err := r.reader.ReadLine(question, nil)
+	r.lineNo++
+	return strings.TrimSpace(answer), err
+}
+
+// Run reads lines and runs them until the input ends or a command quits.
+// Typed on a terminal, errors are shown with OnError and the REPL goes
+// on. From a script, Run stops at the first error and returns it as a
+// *ScriptError. Lines in a script starting with # are comments.
+func (r *REPL) Run() error {
+	if r.reader == nil {
+		r.reader = r.newReader()
+	}
+	interactive := r.reader.Interactive()
+	if interactive {
+		r.loadHistory()
+		defer r.saveHistory()
+	}
+
+	for {
+		line, err := r.reader.ReadLine(r.Prompt, r)
+		r.lineNo++
+		n := r.lineNo
+		if err == errInterrupted {
+			continue
+		}
+		if err == io.EOF {
+			if interactive {
+				r.Println()
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		line = strings.TrimSpace(line)
+		if !interactive && strings.HasPrefix(line, "#") {
+			continue
+		}
+		if interactive {
+			r.addHistory(line)
+		}
+
+		err = r.Exec(line)
+		switch {
+		case err == nil:
+		case errors.Is(err, ErrQuit):
+			return nil
+		case !interactive:
+			return &ScriptError{Line: n, Text: line, Err: err}
+		case r.OnError != nil:
+			r.OnError(err)
+		default:
+			r.Printf("Error: %v\n", err)
+		}
+	}
+}
+
+// RunFile runs the commands in the file at path as a script.
+func (r *REPL) RunFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	in, reader, lineNo := r.In, r.reader, r.lineNo
+	defer func() { r.In, r.reader, r.lineNo = in, reader, lineNo }()
+	r.In, r.reader, r.lineNo = f, nil, 0
+	return r.Run()
+}
+
+func (r *REPL) help(args []string) error {
+	if len(args) == 1 {
+		c, ok := r.commands[args[0]]
+		if !ok {
+			return &UnknownCommandError{Name: args[0], Suggestions: r.suggest(args[0])}
+		}
+		r.Printf("%s\n  %s\n", strings.TrimSpace(c.Name+" "+c.Args), c.Help)
+		return nil
+	}
+
+	width := 0
+	for _, c := range r.commands {
+		width = max(width, textutil.Width(strings.TrimSpace(c.Name+" "+c.Args)))
+	}
+	r.Println("Commands:")
+	for _, name := range r.names() {
+		c := r.commands[name]
+		usage := strings.TrimSpace(c.Name + " " + c.Args)
+		r.Printf("  %s%s  %s\n", usage, strings.Repeat(" ", width-textutil.Width(usage)), c.Help)
+	}
+	return nil
+}
+
+func (r *REPL) showHistory([]string) error {
+	for i, line := range r.history {
+		r.Printf("%4d  %s\n", i+1, line)
+	}
+	return nil
+}
+
+// lineReader reads lines, from a terminal or from a script.
+type lineReader interface {
+	// ReadLine shows prompt, when interactive, and reads a line. With a
+	// completer, tab completes and the arrow keys go through history.
+	ReadLine(prompt string, r *REPL) (string, error)
+	Interactive() bool
+}
+
+func (r *REPL) newReader() lineReader {
+	in := r.In
+	if in == nil {
+		in = os.Stdin
+	}
+	f, ok := in.(*os.File)
+	if ok && isTerminal(f) {
+		if e, err := newEditor(f, r.out()); err == nil {
+			return e
+		}
+		return &scanReader{s: bufio.NewScanner(in), out: r.out(), prompts: true}
+	}
+	return &scanReader{s: bufio.NewScanner(in), out: r.out()}
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// scanReader reads lines without editing, from a script, or from a
+// terminal the editor doesn't support.
+type scanReader struct {
+	s       *bufio.Scanner
+	out     io.Writer
+	prompts bool
+}
+
+func (s *scanReader) ReadLine(prompt string, _ *REPL) (string, error) {
+	if s.prompts {
+		fmt.Fprint(s.out, prompt)
+	}
+	if !s.s.Scan() {
+		if err := s.s.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	return s.s.Text(), nil
+}
+
+func (s *scanReader) Interactive() bool {
+	return s.prompts
+}
@@ -0,0 +1,283 @@
+package repl
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+		err  error
+	}{
+		{"store Jane 555", []string{"store", "Jane", "555"}, nil},
+		{`store "Jane Doe"  555`, []string{"store", "Jane Doe", "555"}, nil},
+		{`store 'Åsa "the boss" L' x`, []string{"store", `Åsa "the boss" L`, "x"}, nil},
+		{`store Jane\ Doe`, []string{"store", "Jane Doe"}, nil},
+		{`add ""`, []string{"add", ""}, nil},
+		{`say "it\"s"`, []string{"say", `it"s`}, nil},
+		{"  ", nil, nil},
+		{`store "Jane`, []string{"store"}, ErrUnclosedQuote},
+	}
+	for _, tt := range tests {
+		got, err := Split(tt.line)
+		if !reflect.DeepEqual(got, tt.want) || err != tt.err {
+			t.Errorf("Split(%q) was incorrect, Actual: %q %v, Expected: %q %v", tt.line, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestQuote(t *testing.T) {
+	for _, s := range []string{"plain", "Jane Doe", `say "hi"`, `back\slash`, ""} {
+		got, _ := Split("x " + Quote(s))
+		if len(got) != 2 || got[1] != s {
+			t.Errorf("Quote(%q) was incorrect, Actual: %q", s, Quote(s))
+		}
+	}
+}
+
+func TestArgCount(t *testing.T) {
+	tests := []struct {
+		usage    string
+		min, max int
+	}{
+		{"", 0, 0},
+		{"<file>", 1, 1},
+		{"<name> [number]", 1, 2},
+		{"<text...>", 1, -1},
+		{"[tags...]", 0, -1},
+	}
+	for _, tt := range tests {
+		if min, max := argCount(tt.usage); min != tt.min || max != tt.max {
+			t.Errorf("argCount(%q) was incorrect, Actual: %d %d, Expected: %d %d", tt.usage, min, max, tt.min, tt.max)
+		}
+	}
+}
+
+// testREPL returns a REPL with the commands add and list, reading in.
+func testREPL(in string) (*REPL, *strings.Builder, *[]string) {
+	out := &strings.Builder{}
+	var items []string
+	r := New("> ")
+	r.In, r.Out = strings.NewReader(in), out
+	r.Add(Command{Name: "add", Args: "<text...>", Help: "add an item", Run: func(args []string) error {
+		items = append(items, strings.Join(args, " "))
+		return nil
+	}})
+	r.Add(Command{Name: "list", Help: "list the items", Run: func([]string) error {
+		for _, item := range items {
+			r.Println(item)
+		}
+		return nil
+	}})
+	r.Add(Command{Name: "ask", Help: "ask a question", Run: func([]string) error {
+		answer, err := r.Ask("Name: ")
+		r.Println("hello", answer)
+		return err
+	}})
+	return r, out, &items
+}
+
+func TestExecErrors(t *testing.T) {
+	r, _, _ := testREPL("")
+	var unknown *UnknownCommandError
+	if err := r.Exec("lsit"); !errors.As(err, &unknown) || !reflect.DeepEqual(unknown.Suggestions, []string{"list"}) {
+		t.Errorf("Exec(lsit) was incorrect, Actual: %v, Expected: did you mean list", err)
+	}
+	if err := r.Exec("a"); !errors.As(err, &unknown) || !reflect.DeepEqual(unknown.Suggestions, []string{"add", "ask"}) {
+		t.Errorf("Exec(a) was incorrect, Actual: %v, Expected: did you mean add or ask", err)
+	}
+	var usage *UsageError
+	err := r.Exec("add")
+	if !errors.As(err, &usage) || err.Error() != "usage: add <text...>" {
+		t.Errorf("Exec(add) was incorrect, Actual: %v, Expected: usage: add <text...>", err)
+	}
+	if err := r.Exec("list all"); !errors.As(err, &usage) {
+		t.Errorf("Exec(list all) was incorrect, Actual: %v, Expected: a usage error", err)
+	}
+	if err := r.Exec("quit"); err != ErrQuit {
+		t.Errorf("Exec(quit) was incorrect, Actual: %v, Expected: %v", err, ErrQuit)
+	}
+}
+
+func TestRunScript(t *testing.T) {
+	r, out, items := testREPL("# a comment\nadd milk\nadd \"brown bread\"\n\nask\nJane Doe\nlist\nquit\nadd never\n")
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"milk", "brown bread"}; !reflect.DeepEqual(*items, want) {
+		t.Errorf("items were incorrect, Actual: %q, Expected: %q", *items, want)
+	}
+	// no prompts in a script
+	if want := "hello Jane Doe\nmilk\nbrown bread\n"; out.String() != want {
+		t.Errorf("output was incorrect, Actual: %q, Expected: %q", out.String(), want)
+	}
+	if len(r.History()) != 0 {
+		t.Errorf("History was incorrect, Actual: %q, Expected: nothing for a script", r.History())
+	}
+}
+
+func TestRunScriptStopsAtError(t *testing.T) {
+	r, _, items := testREPL("add milk\nask\nJane\nlsit\nadd eggs\n")
+	err := r.Run()
+	var se *ScriptError
+	if !errors.As(err, &se) || se.Line != 4 || se.Text != "lsit" {
+		t.Errorf("Run was incorrect, Actual: %v, Expected: an error on line 4", err)
+	}
+	var unknown *UnknownCommandError
+	if !errors.As(err, &unknown) {
+		t.Errorf("Run was incorrect, Actual: %v, Expected: an UnknownCommandError", err)
+	}
+	if len(*items) != 1 {
+		t.Errorf("items were incorrect, Actual: %q, Expected: [milk]", *items)
+	}
+}
+
+func TestRunFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "script.txt")
+	os.WriteFile(path, []byte("add from file\n"), 0644)
+	r, _, items := testREPL("")
+	if err := r.RunFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if len(*items) != 1 || (*items)[0] != "from file" {
+		t.Errorf("RunFile was incorrect, Actual: %q, Expected: [from file]", *items)
+	}
+}
+
+func TestHelp(t *testing.T) {
+	r, out, _ := testREPL("")
+	r.Exec("help add")
+	if want := "add <text...>\n  add an item\n"; out.String() != want {
+		t.Errorf("help add was incorrect, Actual: %q, Expected: %q", out.String(), want)
+	}
+	out.Reset()
+	r.Exec("help")
+	if !strings.Contains(out.String(), "  add <text...>   add an item\n") || !strings.Contains(out.String(), "  quit            leave\n") {
+		t.Errorf("help was incorrect, Actual:\n%s", out.String())
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	r, _, _ := testREPL("")
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"lsit", []string{"list"}},
+		{"LIST", []string{"list"}},
+		{"hlep", []string{"help"}},
+		{"ad", []string{"add"}},
+		{"a", []string{"add", "ask"}},
+		{"h", []string{"help", "history"}},
+		{"xyzzy", nil},
+	}
+	for _, tt := range tests {
+		if got := r.suggest(tt.name); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("suggest(%q) was incorrect, Actual: %q, Expected: %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestComplete(t *testing.T) {
+	r, _, _ := testREPL("")
+	r.Add(Command{Name: "open", Args: "<name>", Run: func([]string) error { return nil },
+		Complete: func(args []string) []string {
+			if len(args) == 0 {
+				return []string{"Jane Doe", "John", "Åsa"}
+			}
+			return nil
+		}})
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"", []string{"add", "ask", "help", "history", "list", "open", "quit"}},
+		{"h", []string{"help", "history"}},
+		{"open j", []string{`"Jane Doe"`, "John"}},
+		{`open "Ja`, []string{`"Jane Doe"`}},
+		{"open å", []string{"Åsa"}},
+		{"open John ", nil},
+		{"help li", []string{"list"}},
+		{"add x", nil},
+		{"'", []string{"add", "ask", "help", "history", "list", "open", "quit"}},
+		{`"h`, []string{"help", "history"}},
+		{"help '", []string{"add", "ask", "help", "history", "list", "open", "quit"}},
+		{`help "hi`, []string{"history"}},
+		{"open 'Ja", []string{`"Jane Doe"`}},
+		{`open 'J`, []string{`"Jane Doe"`, "John"}},
+		{`open \`, []string{`"Jane Doe"`, "John", "Åsa"}},
+	}
+	for _, tt := range tests {
+		if got := r.Complete(tt.line); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Complete(%q) was incorrect, Actual: %q, Expected: %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+// typed runs the REPL as if keys were typed on a terminal.
+func typed(r *REPL, keys string) error {
+	r.reader = &editor{in: bufio.NewReader(strings.NewReader(keys)), out: &strings.Builder{}}
+	return r.Run()
+}
+
+func TestEditor(t *testing.T) {
+	tests := []struct {
+		keys string
+		want []string
+	}{
+		{"add milk\r", []string{"milk"}},
+		{"ad\t milk\r", []string{"milk"}},                                           // tab completes add
+		{"add milkk\x7f\r", []string{"milk"}},                                       // backspace
+		{"add ilk\x1b[D\x1b[D\x1b[Dm\r", []string{"milk"}},                          // left arrow
+		{"add bread\radd milk\r\x1b[A\x1b[A\r", []string{"bread", "milk", "bread"}}, // up arrow
+		{"add junk\x03add milk\r", []string{"milk"}},                                // Ctrl-C drops the line
+		{"add xyz\x15add milk\r", []string{"milk"}},                                 // Ctrl-U clears it
+	}
+	for _, tt := range tests {
+		r, _, items := testREPL("")
+		if err := typed(r, tt.keys); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(*items, tt.want) {
+			t.Errorf("typing %q was incorrect, Actual: %q, Expected: %q", tt.keys, *items, tt.want)
+		}
+	}
+}
+
+func TestInteractiveErrorsContinue(t *testing.T) {
+	r, _, items := testREPL("")
+	var shown []error
+	r.OnError = func(err error) { shown = append(shown, err) }
+	if err := typed(r, "lsit\radd milk\r"); err != nil {
+		t.Fatal(err)
+	}
+	if len(shown) != 1 || len(*items) != 1 {
+		t.Errorf("Run was incorrect, Actual: %v %q, Expected: one error and [milk]", shown, *items)
+	}
+}
+
+func TestHistoryFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	os.WriteFile(path, []byte("add old\n"), 0600)
+
+	r, _, items := testREPL("")
+	r.HistoryFile, r.HistorySize = path, 2
+	if err := typed(r, "\x1b[A\radd new\radd new\r"); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"old", "new", "new"}; !reflect.DeepEqual(*items, want) {
+		t.Errorf("items were incorrect, Actual: %q, Expected: %q", *items, want)
+	}
+	data, _ := os.ReadFile(path)
+	// repeated lines are kept once, and only the last HistorySize lines are saved
+	if want := "add old\nadd new\n"; string(data) != want {
+		t.Errorf("history file was incorrect, Actual: %q, Expected: %q", data, want)
+	}
+}
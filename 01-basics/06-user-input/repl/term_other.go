@@ -0,0 +1,10 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd
+
+package repl
+
+import "errors"
+
+// makeRaw isn't supported here, the REPL reads whole lines instead.
+func makeRaw(fd int) (restore func(), err error) {
+	return nil, errors.New("line editing isn't supported on this system")
+}
@@ -0,0 +1,24 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package repl
+
+import "syscall"
+
+// makeRaw turns off line editing, echo and signals on the terminal fd,
+// so the editor gets every key as it's pressed.
+func makeRaw(fd int) (restore func(), err error) {
+	var old syscall.Termios
+	if err := ioctl(fd, getTermios, &old); err != nil {
+		return nil, err
+	}
+	raw := old
+	raw.Iflag &^= syscall.ICRNL | syscall.IXON | syscall.ISTRIP | syscall.BRKINT | syscall.INPCK
+	raw.Lflag &^= syscall.ECHO | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	raw.Cflag |= syscall.CS8
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+	if err := ioctl(fd, setTermios, &raw); err != nil {
+		return nil, err
+	}
+	return func() { ioctl(fd, setTermios, &old) }, nil
+}
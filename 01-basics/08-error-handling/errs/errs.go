@@ -0,0 +1,200 @@
+// Package errs has one error type for domain errors. Besides a message it
+// carries a code, fields describing the input and the error it was caused
+// by, and it records the stack where it was created.
+//
+// Declare errors once and add details where they happen, errors.Is still
+// finds them:
+//
+//	var ErrTooSmall = errs.New(errs.Invalid, "the number is too small")
+//
+//	return 0, ErrTooSmall.With("no", no)
+//
+//	if errors.Is(err, ErrTooSmall) { ... }
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+)
+
+// Code sorts errors into a few kinds, so a caller can react to them
+// without comparing messages.
+type Code string
+
+const (
+	Invalid    Code = "invalid"    // the input is wrong, the user can fix it
+	NotFound   Code = "not_found"  // something asked for doesn't exist
+	Conflict   Code = "conflict"   // the input clashes with the current state
+	Unexpected Code = "unexpected" // a bug or a failing dependency, the user can't fix it
+)
+
+// Error is a domain error. Create it with New or Wrap.
+type Error struct {
+	Code   Code
+	Msg    string
+	Fields []Field
+	Cause  error
+	stack  []uintptr
+}
+
+// Field is a named value describing the error, like the input that caused it.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// New creates an error. Fields are given as key-value pairs, like with slog:
+// New(errs.Invalid, "the number is too small", "no", -2).
+func New(code Code, msg string, fields ...interface{}) *Error {
+	return &Error{Code: code, Msg: msg, Fields: toFields(fields), stack: callers()}
+}
+
+// Wrap creates an error caused by cause. It returns nil if cause is nil.
+func Wrap(cause error, code Code, msg string, fields ...interface{}) *Error {
+	if cause == nil {
+		return nil
+	}
+	return &Error{Code: code, Msg: msg, Fields: toFields(fields), Cause: cause, stack: callers()}
+}
+
+// With returns a copy of e with more fields, and the stack of where With was called.
+// The copy still matches e with errors.Is.
+func (e *Error) With(fields ...interface{}) *Error {
+	c := *e
+	c.Fields = append(append([]Field(nil), e.Fields...), toFields(fields)...)
+	c.stack = callers()
+	return &c
+}
+
+// Because returns a copy of e caused by cause, see With.
+func (e *Error) Because(cause error) *Error {
+	c := *e
+	c.Cause = cause
+	c.stack = callers()
+	return &c
+}
+
+func toFields(kv []interface{}) []Field {
+	var fields []Field
+	for i := 0; i < len(kv); i += 2 {
+		key, ok := kv[i].(string)
+		if !ok || i+1 == len(kv) {
+			// like slog, a value without a key still shows up
+			fields = append(fields, Field{Key: "!BADKEY", Value: kv[i]})
+			i--
+			continue
+		}
+		fields = append(fields, Field{Key: key, Value: kv[i+1]})
+	}
+	return fields
+}
+
+func callers() []uintptr {
+	pcs := make([]uintptr, 32)
+	// skip runtime.Callers, callers and New, Wrap, With or Because
+	n := runtime.Callers(3, pcs)
+	return pcs[:n]
+}
+
+// Error returns the message, the fields and the cause, for developers and logs.
+func (e *Error) Error() string {
+	var sb strings.Builder
+	sb.WriteString(e.Msg)
+	if len(e.Fields) > 0 {
+		sb.WriteString(" (")
+		for i, f := range e.Fields {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			fmt.Fprintf(&sb, "%s=%v", f.Key, f.Value)
+		}
+		sb.WriteString(")")
+	}
+	if e.Cause != nil {
+		sb.WriteString(": ")
+		sb.WriteString(e.Cause.Error())
+	}
+	return sb.String()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+// Is reports whether target is an *Error with the same code and message,
+// so copies made by With and Because match the error they came from.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code && t.Msg == e.Msg
+}
+
+// Field returns the value of the field named key.
+func (e *Error) Field(key string) (interface{}, bool) {
+	for _, f := range e.Fields {
+		if f.Key == key {
+			return f.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Stack returns the functions that led to the error, the innermost first.
+func (e *Error) Stack() []runtime.Frame {
+	var stack []runtime.Frame
+	frames := runtime.CallersFrames(e.stack)
+	for {
+		frame, more := frames.Next()
+		stack = append(stack, frame)
+		if !more {
+			return stack
+		}
+	}
+}
+
+// As returns the first *Error in err's chain.
+func As(err error) (*Error, bool) {
+	var e *Error
+	ok := errors.As(err, &e)
+	return e, ok
+}
+
+// CodeOf returns the code of the first *Error in err's chain. Other errors are Unexpected.
+func CodeOf(err error) Code {
+	if e, ok := As(err); ok {
+		return e.Code
+	}
+	return Unexpected
+}
+
+// UnexpectedMessage is what users see instead of the details of an unexpected error.
+var UnexpectedMessage = "something went wrong, please try again later"
+
+// Message returns a message meant for users: the message of the first *Error in
+// err's chain, without fields or causes. Unexpected errors only get a general
+// message, their details are for the logs.
+func Message(err error) string {
+	e, ok := As(err)
+	if !ok || e.Code == Unexpected {
+		return UnexpectedMessage
+	}
+	return e.Msg
+}
+
+// LogValue makes slog log the error as a group of its parts.
+func (e *Error) LogValue() slog.Value {
+	attrs := []slog.Attr{slog.String("code", string(e.Code)), slog.String("msg", e.Msg)}
+	for _, f := range e.Fields {
+		attrs = append(attrs, slog.Any(f.Key, f.Value))
+	}
+	if e.Cause != nil {
+		attrs = append(attrs, slog.String("cause", e.Cause.Error()))
+	}
+	if len(e.stack) > 0 {
+		frame := e.Stack()[0]
+		attrs = append(attrs, slog.String("at", fmt.Sprintf("%s:%d", frame.File, frame.Line)))
+	}
+	return slog.GroupValue(attrs...)
+}
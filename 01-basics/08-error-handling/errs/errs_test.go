@@ -0,0 +1,108 @@
+package errs
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+)
+
+var ErrTooSmall = New(Invalid, "the number is too small")
+
+func check(no int) error {
+	if no <= 0 {
+		return ErrTooSmall.With("no", no)
+	}
+	return nil
+}
+
+func TestIsWithFields(t *testing.T) {
+	err := check(-2)
+	if !errors.Is(err, ErrTooSmall) {
+		t.Errorf("errors.Is should match the declared error")
+	}
+	if errors.Is(err, New(Invalid, "another message")) {
+		t.Errorf("errors.Is shouldn't match another message")
+	}
+	if err.Error() != "the number is too small (no=-2)" {
+		t.Errorf("Error was incorrect, Actual: %q", err.Error())
+	}
+	if len(ErrTooSmall.Fields) != 0 {
+		t.Errorf("With shouldn't change the declared error, Actual: %v", ErrTooSmall.Fields)
+	}
+
+	e, ok := As(err)
+	if !ok {
+		t.Fatal("As should find the *Error")
+	}
+	if no, _ := e.Field("no"); no != -2 {
+		t.Errorf("Field was incorrect, Actual: %v, Expected: %v", no, -2)
+	}
+	if frame := e.Stack()[0]; !strings.HasSuffix(frame.Function, "errs.check") {
+		t.Errorf("Stack should start where With was called, Actual: %s", frame.Function)
+	}
+}
+
+func TestWrapAndJoin(t *testing.T) {
+	err := Wrap(fs.ErrNotExist, NotFound, "no such record", "path", "record.csv")
+	if !errors.Is(err, fs.ErrNotExist) || CodeOf(err) != NotFound {
+		t.Errorf("Wrap should keep the cause, Actual: %v", err)
+	}
+	if Wrap(nil, NotFound, "no such record") != nil {
+		t.Errorf("Wrap(nil) should be nil")
+	}
+
+	joined := errors.Join(check(-1), fs.ErrPermission)
+	if !errors.Is(joined, ErrTooSmall) || !errors.Is(joined, fs.ErrPermission) {
+		t.Errorf("errors.Join should keep both errors, Actual: %v", joined)
+	}
+	if CodeOf(joined) != Invalid {
+		t.Errorf("CodeOf was incorrect, Actual: %v, Expected: %v", CodeOf(joined), Invalid)
+	}
+}
+
+func TestMessage(t *testing.T) {
+	cases := []struct {
+		err      error
+		expected string
+	}{
+		{check(-2), "the number is too small"},
+		{Wrap(fs.ErrNotExist, NotFound, "no such record"), "no such record"},
+		{Wrap(fs.ErrPermission, Unexpected, "can't read record.csv"), UnexpectedMessage},
+		{fs.ErrPermission, UnexpectedMessage},
+	}
+	for _, c := range cases {
+		if actual := Message(c.err); actual != c.expected {
+			t.Errorf("Message(%v) was incorrect, Actual: %q, Expected: %q", c.err, actual, c.expected)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	err := ErrTooSmall.With("no", -2).Because(errors.Join(fs.ErrNotExist, New(Conflict, "taken")))
+	data, jsonErr := json.Marshal(err)
+	if jsonErr != nil {
+		t.Fatal(jsonErr)
+	}
+
+	var out struct {
+		Code   string                 `json:"code"`
+		Msg    string                 `json:"message"`
+		Fields map[string]interface{} `json:"fields"`
+		Cause  []interface{}          `json:"cause"`
+		Stack  []string               `json:"stack"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Code != "invalid" || out.Msg != "the number is too small" || out.Fields["no"] != -2.0 {
+		t.Errorf("JSON was incorrect, Actual: %s", data)
+	}
+	if len(out.Cause) != 2 || out.Cause[0] != "file does not exist" {
+		t.Errorf("Joined cause was incorrect, Actual: %v", out.Cause)
+	}
+	if len(out.Stack) == 0 {
+		t.Errorf("JSON should hold the stack")
+	}
+}
@@ -0,0 +1,3 @@
+module error-handling/errs
+
+go 1.21
@@ -0,0 +1,49 @@
+package errs
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type jsonError struct {
+	Code   Code                   `json:"code"`
+	Msg    string                 `json:"message"`
+	Fields map[string]interface{} `json:"fields,omitempty"`
+	Cause  interface{}            `json:"cause,omitempty"`
+	Stack  []string               `json:"stack,omitempty"`
+}
+
+// MarshalJSON writes the error for logs: code, message, fields, cause and
+// stack. A cause that is an *Error is written as an object too, and one
+// made with errors.Join as a list.
+func (e *Error) MarshalJSON() ([]byte, error) {
+	j := jsonError{Code: e.Code, Msg: e.Msg, Cause: causeJSON(e.Cause)}
+	if len(e.Fields) > 0 {
+		j.Fields = map[string]interface{}{}
+		for _, f := range e.Fields {
+			j.Fields[f.Key] = f.Value
+		}
+	}
+	for _, frame := range e.Stack() {
+		if frame.Function != "" {
+			j.Stack = append(j.Stack, fmt.Sprintf("%s %s:%d", frame.Function, frame.File, frame.Line))
+		}
+	}
+	return json.Marshal(j)
+}
+
+func causeJSON(err error) interface{} {
+	switch err := err.(type) {
+	case nil:
+		return nil
+	case *Error:
+		return err
+	case interface{ Unwrap() []error }:
+		var list []interface{}
+		for _, e := range err.Unwrap() {
+			list = append(list, causeJSON(e))
+		}
+		return list
+	}
+	return err.Error()
+}
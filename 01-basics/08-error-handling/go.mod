@@ -0,0 +1,13 @@
+module error-handling
+
+go 1.21
+
+require (
+	error-handling/errs v0.0.0
+	logs v0.0.0
+)
+
+replace (
+	error-handling/errs => ./errs
+	logs => ../../05-misc/01-logs
+)
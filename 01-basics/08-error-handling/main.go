@@ -1,17 +1,18 @@
 package main
 
 import (
-	"errors"
 	"fmt"
+
+	"error-handling/errs"
 )
 
-var NoTooSmall = errors.New("the number is too small")
+var NoTooSmall = errs.New(errs.Invalid, "the number is too small")
 
 func ReturnPositive(no int) (int, error) {
 	if no > 0 {
 		return no, nil
 	} else {
-		return 0, NoTooSmall
+		return 0, NoTooSmall.With("no", no)
 	}
 
 }
@@ -20,7 +21,8 @@ func main() {
 	fmt.Println("hi")
 	no, err := ReturnPositive(-2)
 	if err != nil {
-		fmt.Println(err)
+		fmt.Println(errs.Message(err)) // for the user: the number is too small
+		fmt.Println(err)               // for the logs: the number is too small (no=-2)
 	} else {
 		fmt.Println(no)
 	}
@@ -1,40 +1,56 @@
+//go:build ignore
+
+// Run with: go run panic.go
 package main
 
 import (
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"os"
-	"runtime/debug"
+
+	"error-handling/safe"
+	"logs/logger"
 )
 
-func errorHandler() {
-	if r := recover(); r != nil {
-		// fmt.Println("Recovered ", r)
-		// fmt.Println(string(debug.Stack()))
-		log.Println(r, string(debug.Stack()))
-	}
-}
+var divideLog = slog.Default()
 
 func Divide(nominator int, divider int) float32 {
-	defer errorHandler()
 	if divider == 0 {
 		panic("can't divide by 0")
 	}
 	return float32(nominator) / float32(divider)
 }
 
+// SafeDivide turns the panic of Divide into an error, instead of returning 0.
+func SafeDivide(nominator int, divider int) (float32, error) {
+	return safe.Call(func() float32 {
+		return Divide(nominator, divider)
+	})
+}
+
 func main() {
-	f, err := os.OpenFile("logs", os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	f, err := logger.OpenFile("logs")
 	if err != nil {
-		log.Println(err)
+		fmt.Fprintln(os.Stderr, "Could not log to file:", err)
+		os.Exit(1)
 	}
-	log.SetOutput(f)
+	defer f.Close()
 
-	log.Println("starting program")
-	no := Divide(10, 0)
-	fmt.Println(no)
+	// every entry goes to the terminal and the file
+	divideLog = logger.New(logger.Options{Sinks: []io.Writer{os.Stderr, f}}).For("divide")
 
-	no = Divide(10, 1)
-	fmt.Println(no)
-	f.Close()
+	divideLog.Info("starting program")
+	for _, divider := range []int{0, 1} {
+		no, err := SafeDivide(10, divider)
+		if err != nil {
+			if p, ok := safe.AsPanic(err); ok {
+				divideLog.Error("divide panicked", "divider", divider, "err", err, "stack", string(p.Stack))
+			} else {
+				divideLog.Error("divide failed", "divider", divider, "err", err)
+			}
+			continue
+		}
+		fmt.Println(no)
+	}
 }
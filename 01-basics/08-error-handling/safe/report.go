@@ -0,0 +1,65 @@
+package safe
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// WriteReport writes a crash report for p into dir and returns its path.
+// Besides the panic and its stack, the report holds what's needed to
+// reproduce it: the time, the Go version, the platform and the build info,
+// like the module versions and the VCS revision.
+func WriteReport(dir string, p *PanicError) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	now := time.Now()
+	f, err := os.CreateTemp(dir, "crash-"+now.Format("20060102-150405")+"-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "panic: %v\n\n", p.Value)
+	fmt.Fprintf(&sb, "time:     %s\n", now.Format(time.RFC3339))
+	fmt.Fprintf(&sb, "go:       %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	if len(os.Args) > 0 {
+		fmt.Fprintf(&sb, "command:  %s\n", strings.Join(os.Args, " "))
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		fmt.Fprintf(&sb, "module:   %s %s\n", info.Main.Path, info.Main.Version)
+		for _, s := range info.Settings {
+			if strings.HasPrefix(s.Key, "vcs.") {
+				fmt.Fprintf(&sb, "%-9s %s\n", s.Key+":", s.Value)
+			}
+		}
+		for _, dep := range info.Deps {
+			fmt.Fprintf(&sb, "dep:      %s %s\n", dep.Path, dep.Version)
+		}
+	}
+	fmt.Fprintf(&sb, "\n%s", p.Stack)
+
+	if _, err := f.WriteString(sb.String()); err != nil {
+		return "", err
+	}
+	return f.Name(), f.Close()
+}
+
+// ReportHandler returns a handler for SetHandler that writes a crash
+// report into dir and logs where it went.
+func ReportHandler(dir string) func(*PanicError) {
+	return func(p *PanicError) {
+		path, err := WriteReport(dir, p)
+		if err != nil {
+			LogHandler(p)
+			return
+		}
+		slog.Error("recovered from panic", "panic", fmt.Sprint(p.Value), "report", path)
+	}
+}
@@ -0,0 +1,110 @@
+// Package safe turns panics into errors, so a caller finds out something
+// went wrong instead of getting a zero value.
+//
+//	no, err := safe.Call(func() float32 { return Divide(10, 0) })
+//	if err != nil {
+//		// err is a *safe.PanicError holding the panic value and the stack
+//	}
+package safe
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+)
+
+// PanicError is a recovered panic.
+type PanicError struct {
+	Value interface{} // what was passed to panic()
+	Stack []byte      // the stack of the goroutine that panicked
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it's an error, so errors.Is and
+// errors.As see through a panic(err).
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+func recovered(r interface{}) *PanicError {
+	return &PanicError{Value: r, Stack: debug.Stack()}
+}
+
+// Call runs fn and returns its result, or the panic it raised as a *PanicError.
+func Call[T any](fn func() T) (result T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recovered(r)
+		}
+	}()
+	return fn(), nil
+}
+
+// CallErr is Call for functions that return an error themselves.
+func CallErr[T any](fn func() (T, error)) (result T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recovered(r)
+		}
+	}()
+	return fn()
+}
+
+// Do runs fn and returns the panic it raised, if any.
+func Do(fn func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recovered(r)
+		}
+	}()
+	fn()
+	return nil
+}
+
+// AsPanic returns the *PanicError in err's chain.
+func AsPanic(err error) (*PanicError, bool) {
+	var p *PanicError
+	ok := errors.As(err, &p)
+	return p, ok
+}
+
+var (
+	mu      sync.RWMutex
+	handler = LogHandler
+)
+
+// SetHandler changes what Go does with a panic, nil restores LogHandler.
+func SetHandler(h func(*PanicError)) {
+	mu.Lock()
+	defer mu.Unlock()
+	if h == nil {
+		h = LogHandler
+	}
+	handler = h
+}
+
+// LogHandler logs the panic and its stack with slog, at error level.
+func LogHandler(p *PanicError) {
+	slog.Error("recovered from panic", "panic", fmt.Sprint(p.Value), "stack", string(p.Stack))
+}
+
+// Go runs fn on a new goroutine. A panic doesn't crash the program, it's
+// passed to the handler set with SetHandler.
+func Go(fn func()) {
+	go func() {
+		if err := Do(fn); err != nil {
+			mu.RLock()
+			h := handler
+			mu.RUnlock()
+			h(err.(*PanicError))
+		}
+	}()
+}
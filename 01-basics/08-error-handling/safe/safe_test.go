@@ -0,0 +1,81 @@
+package safe
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func divide(nominator int, divider int) float32 {
+	if divider == 0 {
+		panic("can't divide by 0")
+	}
+	return float32(nominator) / float32(divider)
+}
+
+func TestCall(t *testing.T) {
+	no, err := Call(func() float32 { return divide(10, 2) })
+	if err != nil || no != 5 {
+		t.Errorf("Call was incorrect, Actual: %v %v, Expected: 5 <nil>", no, err)
+	}
+
+	no, err = Call(func() float32 { return divide(10, 0) })
+	p, ok := AsPanic(err)
+	if !ok {
+		t.Fatalf("Call should return a *PanicError, Actual: %v", err)
+	}
+	if p.Value != "can't divide by 0" || err.Error() != "panic: can't divide by 0" {
+		t.Errorf("PanicError was incorrect, Actual: %v", p.Value)
+	}
+	if !strings.Contains(string(p.Stack), "safe.divide") {
+		t.Errorf("Stack should show where the panic started, Actual: %s", p.Stack)
+	}
+}
+
+func TestUnwrap(t *testing.T) {
+	err := Do(func() { panic(fs.ErrNotExist) })
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("errors.Is should see the panic value, Actual: %v", err)
+	}
+
+	_, err = CallErr(func() (int, error) { return 0, fs.ErrPermission })
+	if _, ok := AsPanic(err); ok || err != fs.ErrPermission {
+		t.Errorf("CallErr should pass errors through, Actual: %v", err)
+	}
+}
+
+func TestGo(t *testing.T) {
+	panics := make(chan *PanicError, 1)
+	SetHandler(func(p *PanicError) { panics <- p })
+	defer SetHandler(nil)
+
+	Go(func() { panic("in a goroutine") })
+	select {
+	case p := <-panics:
+		if p.Value != "in a goroutine" {
+			t.Errorf("Handler got the wrong panic, Actual: %v", p.Value)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handler wasn't called")
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	err := Do(func() { panic("boom") })
+	p, _ := AsPanic(err)
+
+	path, err := WriteReport(t.TempDir(), p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	report := string(data)
+	for _, expected := range []string{"panic: boom", "go:       go", "goroutine "} {
+		if !strings.Contains(report, expected) {
+			t.Errorf("Report should contain %q, Actual: %s", expected, report)
+		}
+	}
+}
@@ -1,35 +1,272 @@
+//go:build ignore
+
+// Run with: go run assignment.go [-file todo.json] [-history file] [script]
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"arrays/todo"
+	"error-handling/errs"
+	"input/repl"
 )
 
 func main() {
-	// create array
-	arr := make([]string, 0)
-
-	var response string
-	for {
-		fmt.Print("command> ")
-		fmt.Scan(&response)
-		if response == "quit" {
-			break
-		} else if response == "new" {
-			fmt.Print("Entry:")
-			fmt.Scan(&response)
-			arr = append(arr, response)
-			// save entry to list
-			fmt.Println("Saving entry")
-		} else if response == "list" {
-			// list entries
-			fmt.Println("Listing entries")
-			for i := 0; i < len(arr); i++ {
-				fmt.Println(arr[i])
+	file := flag.String("file", "todo.json", "where the to-do list is saved")
+	history := flag.String("history", "", "keep what you type in this file between runs, like ~/.todo_history")
+	flag.Parse()
+
+	list, err := todo.Open(*file)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	r := repl.New("command> ")
+	r.HistoryFile = *history
+	r.OnError = func(err error) { report(r, err) }
+	addCommands(r, list)
+
+	if flag.NArg() > 0 {
+		err = r.RunFile(flag.Arg(0))
+	} else {
+		err = r.Run()
+	}
+	if err != nil {
+		report(r, err)
+		os.Exit(1)
+	}
+	fmt.Println("bye")
+}
+
+// options takes the options out of args, like --due friday or --due=friday.
+func options(args []string, names ...string) (rest []string, opts map[string]string, err error) {
+	opts = map[string]string{}
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(args[i], "--"), "=")
+		if !strings.HasPrefix(args[i], "--") {
+			rest = append(rest, args[i])
+			continue
+		}
+		known := false
+		for _, n := range names {
+			known = known || n == name
+		}
+		if !known {
+			return nil, nil, fmt.Errorf("unknown option --%s", name)
+		}
+		if !hasValue {
+			if i+1 == len(args) {
+				return nil, nil, fmt.Errorf("--%s needs a value", name)
 			}
+			i++
+			value = args[i]
+		}
+		opts[name] = value
+	}
+	return rest, opts, nil
+}
+
+// apply sets the due date and priority given as options.
+func apply(item *todo.Item, opts map[string]string) error {
+	if due, ok := opts["due"]; ok {
+		if due == "none" || due == "" {
+			item.Due = time.Time{}
 		} else {
-			fmt.Println("Unknown command", response)
+			t, err := todo.ParseDue(due, time.Now())
+			if err != nil {
+				return err
+			}
+			item.Due = t
+		}
+	}
+	if p, ok := opts["priority"]; ok {
+		priority, err := todo.ParsePriority(p)
+		if err != nil {
+			return err
+		}
+		item.Priority = priority
+	}
+	return nil
+}
+
+// parseIds reads the ids in args, and checks they are all in the list,
+// so a command can change all of them or none.
+func parseIds(list *todo.List, args []string) ([]int, error) {
+	var ids []int
+	seen := map[int]bool{}
+	for _, arg := range args {
+		id, err := strconv.Atoi(strings.TrimPrefix(arg, "#"))
+		if err != nil {
+			return nil, fmt.Errorf("%q isn't an id, use the number list shows", arg)
+		}
+		if _, err := list.Get(id); err != nil {
+			return nil, err
 		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
 
+func addCommands(r *repl.REPL, list *todo.List) {
+	// save runs a command, and saves the list if it changed it
+	save := func(run func(args []string) error) func(args []string) error {
+		return func(args []string) error {
+			if err := run(args); err != nil {
+				return err
+			}
+			return list.Save()
+		}
 	}
-	fmt.Println("bye")
+	completeOptions := func(args []string) []string {
+		if len(args) > 0 {
+			switch args[len(args)-1] {
+			case "--priority":
+				return []string{"low", "normal", "high"}
+			case "--due":
+				return []string{"today", "tomorrow", "none"}
+			}
+		}
+		return []string{"--due", "--priority"}
+	}
+
+	r.Add(repl.Command{Name: "add", Args: "<text...> [--due date] [--priority p]", Help: "add an item, due like 2024-05-03, today, friday or +3", Complete: completeOptions, Run: save(func(args []string) error {
+		rest, opts, err := options(args, "due", "priority")
+		if err != nil {
+			return err
+		}
+		item := todo.Item{Text: strings.Join(rest, " ")}
+		if err := apply(&item, opts); err != nil {
+			return err
+		}
+		item, err = list.Add(item)
+		if err != nil {
+			return err
+		}
+		r.Printf("Added %d\n", item.Id)
+		return nil
+	})})
+	r.Add(repl.Command{Name: "done", Args: "<id...>", Help: "mark items as done", Run: save(func(args []string) error {
+		ids, err := parseIds(list, args)
+		if err != nil {
+			return err
+		}
+		return list.Batch("done"+" "+strings.Trim(fmt.Sprint(ids), "[]"), func() error {
+			for _, id := range ids {
+				if err := list.Done(id); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})})
+	r.Add(repl.Command{Name: "remove", Args: "<id...>", Help: "remove items", Run: save(func(args []string) error {
+		ids, err := parseIds(list, args)
+		if err != nil {
+			return err
+		}
+		return list.Batch("remove"+" "+strings.Trim(fmt.Sprint(ids), "[]"), func() error {
+			for _, id := range ids {
+				if err := list.Remove(id); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})})
+	var edit repl.Command
+	edit = repl.Command{Name: "edit", Args: "<id> [text...] [--due date] [--priority p]", Help: "change the text, due date or priority of an item", Complete: completeOptions, Run: save(func(args []string) error {
+		rest, opts, err := options(args, "due", "priority")
+		if err != nil {
+			return err
+		}
+		if len(rest) == 0 {
+			return &repl.UsageError{Command: edit}
+		}
+		id, err := parseIds(list, rest[:1])
+		if err != nil {
+			return err
+		}
+		item, err := list.Get(id[0])
+		if err != nil {
+			return err
+		}
+		if len(rest) > 1 {
+			item.Text = strings.Join(rest[1:], " ")
+		}
+		if err := apply(&item, opts); err != nil {
+			return err
+		}
+		return list.Edit(id[0], func(edited *todo.Item) { *edited = item })
+	})}
+	r.Add(edit)
+	r.Add(repl.Command{Name: "list", Args: "[--filter expr]", Help: "list the items, filter like open,high,due:friday,overdue or a text", Run: func(args []string) error {
+		_, opts, err := options(args, "filter")
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		f, err := todo.ParseFilter(opts["filter"], now)
+		if err != nil {
+			return err
+		}
+		for _, item := range list.Items(f) {
+			show(r, item, now)
+		}
+		return nil
+	}})
+	r.Add(repl.Command{Name: "undo", Help: "undo the last change", Run: save(func([]string) error {
+		action, err := list.Undo()
+		if err != nil {
+			return err
+		}
+		r.Println("Undid", action)
+		return nil
+	})})
+}
+
+func show(r *repl.REPL, item todo.Item, now time.Time) {
+	mark := " "
+	if item.Done {
+		mark = "x"
+	}
+	line := fmt.Sprintf("%3d [%s] %s", item.Id, mark, item.Text)
+	if !item.Due.IsZero() {
+		line += ", due " + item.Due.Format("Mon "+todo.DateLayout)
+		if item.Overdue(now) {
+			line += " (overdue)"
+		}
+	}
+	if item.Priority != todo.Normal {
+		line += ", " + item.Priority.String() + " priority"
+	}
+	r.Println(line)
+}
+
+func report(r *repl.REPL, err error) {
+	var script *repl.ScriptError
+	if errors.As(err, &script) {
+		r.Printf("Line %d: %s\n", script.Line, script.Text)
+		err = script.Err
+	}
+	if errors.Is(err, todo.ErrNotFound) {
+		e, _ := errs.As(err)
+		id, _ := e.Field("id")
+		r.Printf("No item with id %v\n", id)
+		return
+	}
+	if _, ok := errs.As(err); ok && errs.CodeOf(err) != errs.Unexpected {
+		r.Println(errs.Message(err))
+		return
+	}
+	r.Println(err)
 }
@@ -0,0 +1,15 @@
+module arrays
+
+go 1.21
+
+require (
+	error-handling/errs v0.0.0
+	input v0.0.0
+	strings-example v0.0.0
+)
+
+replace (
+	error-handling/errs => ../../01-basics/08-error-handling/errs
+	input => ../../01-basics/06-user-input
+	strings-example => ../../05-misc/02-strings
+)
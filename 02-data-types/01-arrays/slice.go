@@ -1,3 +1,6 @@
+//go:build ignore
+
+// Run with: go run slice.go
 package main
 
 import "fmt"
@@ -0,0 +1,120 @@
+package todo
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"error-handling/errs"
+)
+
+// DateLayout is how due dates are written.
+const DateLayout = "2006-01-02"
+
+// Day returns the start of the day t is in.
+func Day(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// ParseDue reads a due date: a date like 2024-05-03, today, tomorrow, a
+// weekday like friday, the next one, or a number of days like +3.
+func ParseDue(s string, now time.Time) (time.Time, error) {
+	today := Day(now)
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch s {
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if name := strings.ToLower(d.String()); s == name || s == name[:3] {
+			days := (int(d) - int(today.Weekday()) + 7) % 7
+			if days == 0 {
+				days = 7
+			}
+			return today.AddDate(0, 0, days), nil
+		}
+	}
+	if strings.HasPrefix(s, "+") {
+		if n, err := strconv.Atoi(strings.TrimSuffix(s[1:], "d")); err == nil {
+			return today.AddDate(0, 0, n), nil
+		}
+	}
+	t, err := time.ParseInLocation(DateLayout, s, now.Location())
+	if err != nil {
+		return time.Time{}, errs.New(errs.Invalid, "a due date is like 2024-05-03, today, tomorrow, friday or +3", "due", s)
+	}
+	return t, nil
+}
+
+// Filter picks items. Its zero value picks all of them.
+type Filter struct {
+	Done     *bool     // only done, or only not done, items
+	Priority *Priority // only items with this priority
+	DueBy    time.Time // only items due on this day or before
+	Overdue  bool      // only items past their due date
+	Text     string    // only items with this text in them, ignoring case
+
+	now time.Time
+}
+
+// ParseFilter reads a filter like "open,high,due:friday,milk". It
+// understands:
+//
+//	open, done          items still to do, or done
+//	low, normal, high   items with that priority
+//	overdue             items past their due date
+//	due:<date>          items due on that day or before, see ParseDue
+//
+// Anything else is text to look for.
+func ParseFilter(s string, now time.Time) (Filter, error) {
+	f := Filter{now: now}
+	var text []string
+	for _, term := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' }) {
+		lower := strings.ToLower(term)
+		switch {
+		case lower == "open" || lower == "done":
+			done := lower == "done"
+			f.Done = &done
+		case lower == "overdue":
+			f.Overdue = true
+		case strings.HasPrefix(lower, "due:"):
+			due, err := ParseDue(lower[len("due:"):], now)
+			if err != nil {
+				return Filter{}, err
+			}
+			f.DueBy = due
+		default:
+			if p, err := ParsePriority(lower); err == nil {
+				f.Priority = &p
+				continue
+			}
+			text = append(text, term)
+		}
+	}
+	f.Text = strings.Join(text, " ")
+	return f, nil
+}
+
+// Match reports whether f picks item.
+func (f Filter) Match(item Item) bool {
+	now := f.now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	switch {
+	case f.Done != nil && item.Done != *f.Done:
+		return false
+	case f.Priority != nil && item.Priority != *f.Priority:
+		return false
+	case !f.DueBy.IsZero() && (item.Due.IsZero() || item.Due.After(f.DueBy)):
+		return false
+	case f.Overdue && !item.Overdue(now):
+		return false
+	case f.Text != "" && !strings.Contains(strings.ToLower(item.Text), strings.ToLower(f.Text)):
+		return false
+	}
+	return true
+}
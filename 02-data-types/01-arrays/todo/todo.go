@@ -0,0 +1,304 @@
+// Package todo is a to-do list: items with a due date and a priority,
+// kept in a slice and saved to a JSON file. Every change can be undone.
+package todo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"error-handling/errs"
+)
+
+var (
+	ErrNotFound      = errs.New(errs.NotFound, "no such item")
+	ErrNoText        = errs.New(errs.Invalid, "an item needs a text")
+	ErrNothingToUndo = errs.New(errs.Invalid, "nothing to undo")
+)
+
+// Priority says how important an item is. The zero value is Normal.
+type Priority int
+
+const (
+	Low Priority = iota - 1
+	Normal
+	High
+)
+
+var priorityNames = map[Priority]string{Low: "low", Normal: "normal", High: "high"}
+
+func (p Priority) String() string {
+	if name, ok := priorityNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("Priority(%d)", int(p))
+}
+
+// ParsePriority returns the priority called name: low, normal or high.
+func ParsePriority(name string) (Priority, error) {
+	for p, n := range priorityNames {
+		if strings.EqualFold(name, n) {
+			return p, nil
+		}
+	}
+	return Normal, errs.New(errs.Invalid, "the priority is low, normal or high", "priority", name)
+}
+
+func (p Priority) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+func (p *Priority) UnmarshalText(text []byte) error {
+	var err error
+	*p, err = ParsePriority(string(text))
+	return err
+}
+
+// Item is something to do.
+type Item struct {
+	Id       int       `json:"id"`
+	Text     string    `json:"text"`
+	Done     bool      `json:"done,omitempty"`
+	Due      time.Time `json:"due"`
+	Priority Priority  `json:"priority,omitempty"`
+}
+
+// MarshalJSON leaves out the due date when there is none, omitempty
+// doesn't do that for a time.Time.
+func (i Item) MarshalJSON() ([]byte, error) {
+	type item Item // without the MarshalJSON method
+	var due *time.Time
+	if !i.Due.IsZero() {
+		due = &i.Due
+	}
+	return json.Marshal(struct {
+		item
+		Due *time.Time `json:"due,omitempty"`
+	}{item(i), due})
+}
+
+// same reports whether a and b are the same. == would also compare the
+// time zones of the due dates, Equal only compares the moments.
+func same(a, b Item) bool {
+	return a.Id == b.Id && a.Text == b.Text && a.Done == b.Done && a.Due.Equal(b.Due) && a.Priority == b.Priority
+}
+
+// Overdue reports whether it's past the day i was due, and i isn't done.
+func (i Item) Overdue(now time.Time) bool {
+	return !i.Done && !i.Due.IsZero() && i.Due.Before(Day(now))
+}
+
+// change is a state of the list to go back to, and what changed it.
+type change struct {
+	action string
+	items  []Item
+	nextId int
+}
+
+// List is a to-do list.
+type List struct {
+	path   string
+	items  []Item
+	nextId int
+	undo   []change
+}
+
+// file is how a List is saved.
+type file struct {
+	NextId int    `json:"next_id"`
+	Items  []Item `json:"items"`
+}
+
+// New returns an empty list that isn't saved anywhere.
+func New() *List {
+	return &List{nextId: 1}
+}
+
+// Open reads the list saved in path. A file that doesn't exist yet gives
+// an empty list, Save creates it.
+func Open(path string) (*List, error) {
+	l := New()
+	l.path = path
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, errs.Wrap(err, errs.Invalid, "the to-do file is broken", "path", path)
+	}
+	l.items = f.Items
+	l.nextId = max(f.NextId, 1)
+	for _, item := range l.items {
+		l.nextId = max(l.nextId, item.Id+1)
+	}
+	return l, nil
+}
+
+// Save writes the list to the file it was opened from, through a
+// temporary file so a crash can't leave half a list behind.
+func (l *List) Save() error {
+	if l.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(file{NextId: l.nextId, Items: l.items}, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(l.path), ".todo-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), l.path)
+}
+
+// remember saves the current state, so Undo can go back to it.
+func (l *List) remember(action string) {
+	l.undo = append(l.undo, change{action: action, items: append([]Item(nil), l.items...), nextId: l.nextId})
+}
+
+// Undo goes back to before the last change, and returns what that change was.
+func (l *List) Undo() (string, error) {
+	if len(l.undo) == 0 {
+		return "", ErrNothingToUndo
+	}
+	last := l.undo[len(l.undo)-1]
+	l.undo = l.undo[:len(l.undo)-1]
+	l.items, l.nextId = last.items, last.nextId
+	return last.action, nil
+}
+
+// Batch runs fn, which can make several changes, as a single change: one
+// Undo takes all of them back and reports action. When fn fails, the list is
+// left as it was. fn must not call Undo.
+func (l *List) Batch(action string, fn func() error) error {
+	n := len(l.undo)
+	before := change{action: action, items: append([]Item(nil), l.items...), nextId: l.nextId}
+	if err := fn(); err != nil {
+		l.items, l.nextId, l.undo = before.items, before.nextId, l.undo[:n]
+		return err
+	}
+	if len(l.undo) > n {
+		l.undo = append(l.undo[:n], before)
+	}
+	return nil
+}
+
+func (l *List) index(id int) (int, error) {
+	for i, item := range l.items {
+		if item.Id == id {
+			return i, nil
+		}
+	}
+	return -1, ErrNotFound.With("id", id)
+}
+
+// Add adds an item and returns it, with its id.
+func (l *List) Add(item Item) (Item, error) {
+	item.Text = strings.TrimSpace(item.Text)
+	if item.Text == "" {
+		return Item{}, ErrNoText
+	}
+	l.remember(fmt.Sprintf("add %q", item.Text))
+	item.Id = l.nextId
+	l.nextId++
+	l.items = append(l.items, item)
+	return item, nil
+}
+
+// Get returns the item with id.
+func (l *List) Get(id int) (Item, error) {
+	i, err := l.index(id)
+	if err != nil {
+		return Item{}, err
+	}
+	return l.items[i], nil
+}
+
+// Done marks the item with id as done.
+func (l *List) Done(id int) error {
+	return l.Edit(id, func(item *Item) { item.Done = true })
+}
+
+// Remove removes the item with id.
+func (l *List) Remove(id int) error {
+	i, err := l.index(id)
+	if err != nil {
+		return err
+	}
+	l.remember(fmt.Sprintf("remove %d", id))
+	l.items = append(l.items[:i:i], l.items[i+1:]...)
+	return nil
+}
+
+// Edit changes the item with id with change. The id can't be changed.
+func (l *List) Edit(id int, change func(item *Item)) error {
+	i, err := l.index(id)
+	if err != nil {
+		return err
+	}
+	item := l.items[i]
+	change(&item)
+	item.Id = id
+	item.Text = strings.TrimSpace(item.Text)
+	if item.Text == "" {
+		return ErrNoText
+	}
+	if same(item, l.items[i]) {
+		return nil
+	}
+	action := fmt.Sprintf("edit %d", id)
+	if item.Done && !l.items[i].Done {
+		action = fmt.Sprintf("done %d", id)
+	}
+	l.remember(action)
+	l.items[i] = item
+	return nil
+}
+
+// Len returns the number of items, done or not.
+func (l *List) Len() int {
+	return len(l.items)
+}
+
+// Items returns the items f matches, the ones still to do first, then by
+// due date, priority and id.
+func (l *List) Items(f Filter) []Item {
+	var list []Item
+	for _, item := range l.items {
+		if f.Match(item) {
+			list = append(list, item)
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		switch {
+		case a.Done != b.Done:
+			return !a.Done
+		case !a.Due.Equal(b.Due):
+			// items without a due date go last
+			return !a.Due.IsZero() && (b.Due.IsZero() || a.Due.Before(b.Due))
+		case a.Priority != b.Priority:
+			return a.Priority > b.Priority
+		}
+		return a.Id < b.Id
+	})
+	return list
+}
@@ -0,0 +1,286 @@
+package todo
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// a Wednesday
+var now = time.Date(2024, 5, 1, 15, 30, 0, 0, time.UTC)
+
+func date(s string) time.Time {
+	t, _ := time.Parse(DateLayout, s)
+	return t
+}
+
+func testList(t *testing.T) *List {
+	t.Helper()
+	l := New()
+	for _, item := range []Item{
+		{Text: "buy milk", Due: date("2024-05-01"), Priority: High},
+		{Text: "call Åsa", Due: date("2024-04-20")},
+		{Text: "read a book", Priority: Low},
+		{Text: "pay rent", Due: date("2024-05-03")},
+	} {
+		if _, err := l.Add(item); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return l
+}
+
+func idsOf(items []Item) []int {
+	var ids []int
+	for _, item := range items {
+		ids = append(ids, item.Id)
+	}
+	return ids
+}
+
+func TestAdd(t *testing.T) {
+	l := New()
+	item, err := l.Add(Item{Text: "  buy milk "})
+	if err != nil || item.Id != 1 || item.Text != "buy milk" {
+		t.Errorf("Add was incorrect, Actual: %+v %v, Expected: id 1, buy milk", item, err)
+	}
+	if _, err := l.Add(Item{Text: " "}); !errors.Is(err, ErrNoText) {
+		t.Errorf("Add without text was incorrect, Actual: %v, Expected: %v", err, ErrNoText)
+	}
+}
+
+func TestItemsOrder(t *testing.T) {
+	l := testList(t)
+	l.Done(2)
+	// open first, by due date, no due date last, done at the end
+	if got, want := idsOf(l.Items(Filter{})), []int{1, 4, 3, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Items was incorrect, Actual: %v, Expected: %v", got, want)
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	l := testList(t)
+	l.Done(4)
+	tests := []struct {
+		filter string
+		want   []int
+	}{
+		{"", []int{2, 1, 3, 4}},
+		{"open", []int{2, 1, 3}},
+		{"done", []int{4}},
+		{"high", []int{1}},
+		{"overdue", []int{2}},
+		{"due:today", []int{2, 1}},
+		{"due:friday", []int{2, 1, 4}},
+		{"open,due:friday", []int{2, 1}},
+		{"ÅSA", []int{2}},
+		{"a book", []int{3}},
+		{"open low book", []int{3}},
+		{"nothing like this", nil},
+	}
+	for _, tt := range tests {
+		f, err := ParseFilter(tt.filter, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := idsOf(l.Items(f)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseFilter(%q) was incorrect, Actual: %v, Expected: %v", tt.filter, got, tt.want)
+		}
+	}
+	if _, err := ParseFilter("due:someday", now); err == nil {
+		t.Errorf("ParseFilter(due:someday) was incorrect, Actual: nil, Expected: error")
+	}
+}
+
+func TestParseDue(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"today", "2024-05-01"},
+		{"Tomorrow", "2024-05-02"},
+		{"friday", "2024-05-03"},
+		{"wed", "2024-05-08"},
+		{"+3", "2024-05-04"},
+		{"+10d", "2024-05-11"},
+		{"2024-12-24", "2024-12-24"},
+	}
+	for _, tt := range tests {
+		got, err := ParseDue(tt.s, now)
+		if err != nil || got.Format(DateLayout) != tt.want {
+			t.Errorf("ParseDue(%q) was incorrect, Actual: %v %v, Expected: %s", tt.s, got, err, tt.want)
+		}
+	}
+	if _, err := ParseDue("24/12", now); err == nil {
+		t.Errorf("ParseDue(24/12) was incorrect, Actual: nil, Expected: error")
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	l := testList(t)
+	_, getErr := l.Get(9)
+	for _, err := range []error{getErr, l.Done(9), l.Remove(9), l.Edit(9, func(*Item) {})} {
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("was incorrect, Actual: %v, Expected: %v", err, ErrNotFound)
+		}
+	}
+}
+
+func TestEdit(t *testing.T) {
+	l := testList(t)
+	err := l.Edit(3, func(item *Item) {
+		item.Id = 99
+		item.Text = "read two books"
+		item.Priority = High
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	item, _ := l.Get(3)
+	if item.Text != "read two books" || item.Priority != High {
+		t.Errorf("Edit was incorrect, Actual: %+v", item)
+	}
+	if err := l.Edit(3, func(item *Item) { item.Text = "" }); !errors.Is(err, ErrNoText) {
+		t.Errorf("Edit without text was incorrect, Actual: %v, Expected: %v", err, ErrNoText)
+	}
+}
+
+func TestUndo(t *testing.T) {
+	l := testList(t)
+	before := l.Items(Filter{})
+	l.Done(1)
+	l.Remove(2)
+	l.Edit(3, func(item *Item) { item.Text = "changed" })
+	l.Edit(4, func(item *Item) {}) // no change, nothing to undo
+
+	for _, want := range []string{"edit 3", "remove 2", "done 1"} {
+		action, err := l.Undo()
+		if err != nil || action != want {
+			t.Errorf("Undo was incorrect, Actual: %q %v, Expected: %q", action, err, want)
+		}
+	}
+	if got := l.Items(Filter{}); !reflect.DeepEqual(got, before) {
+		t.Errorf("Undo was incorrect, Actual: %+v, Expected: %+v", got, before)
+	}
+
+	for i := 0; i < 4; i++ {
+		l.Undo()
+	}
+	if _, err := l.Undo(); !errors.Is(err, ErrNothingToUndo) || l.Len() != 0 {
+		t.Errorf("Undo was incorrect, Actual: %v %d items, Expected: %v", err, l.Len(), ErrNothingToUndo)
+	}
+	// undoing an add gives its id back
+	if item, _ := l.Add(Item{Text: "again"}); item.Id != 1 {
+		t.Errorf("Add after Undo was incorrect, Actual: id %d, Expected: 1", item.Id)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	l := testList(t)
+	before := l.Items(Filter{})
+	err := l.Batch("done 1 2", func() error {
+		if err := l.Done(1); err != nil {
+			return err
+		}
+		return l.Done(2)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if action, err := l.Undo(); err != nil || action != "done 1 2" {
+		t.Errorf("Undo of a batch was incorrect, Actual: %q %v, Expected: %q", action, err, "done 1 2")
+	}
+	if got := l.Items(Filter{}); !reflect.DeepEqual(got, before) {
+		t.Errorf("Undo of a batch was incorrect, Actual: %+v, Expected: %+v", got, before)
+	}
+
+	// a batch that fails changes nothing
+	err = l.Batch("remove 1 99", func() error {
+		if err := l.Remove(1); err != nil {
+			return err
+		}
+		return l.Remove(99)
+	})
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Batch was incorrect, Actual: %v, Expected: %v", err, ErrNotFound)
+	}
+	if got := l.Items(Filter{}); !reflect.DeepEqual(got, before) {
+		t.Errorf("Failed batch was incorrect, Actual: %+v, Expected: %+v", got, before)
+	}
+	if action, _ := l.Undo(); action != `add "pay rent"` {
+		t.Errorf("Undo after a failed batch was incorrect, Actual: %q, Expected: %q", action, `add "pay rent"`)
+	}
+}
+
+func TestSaveAndOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.json")
+	l, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, item := range testList(t).Items(Filter{}) {
+		l.Add(item)
+	}
+	l.Done(2)
+	l.Remove(4)
+	if err := l.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	again, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again.Items(Filter{}), l.Items(Filter{})) {
+		t.Errorf("Open was incorrect, Actual: %+v, Expected: %+v", again.Items(Filter{}), l.Items(Filter{}))
+	}
+	// ids aren't reused, not even the removed one
+	if item, _ := again.Add(Item{Text: "new"}); item.Id != 5 {
+		t.Errorf("Add after Open was incorrect, Actual: id %d, Expected: 5", item.Id)
+	}
+}
+
+func TestItemJSON(t *testing.T) {
+	tests := []struct {
+		item Item
+		want string
+	}{
+		{Item{Id: 1, Text: "read a book"}, `{"id":1,"text":"read a book"}`},
+		{Item{Id: 2, Text: "buy milk", Done: true, Due: date("2024-05-01"), Priority: High}, `{"id":2,"text":"buy milk","done":true,"priority":"high","due":"2024-05-01T00:00:00Z"}`},
+	}
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.item)
+		if err != nil || string(data) != tt.want {
+			t.Errorf("Marshal was incorrect, Actual: %s %v, Expected: %s", data, err, tt.want)
+		}
+		var back Item
+		if err := json.Unmarshal(data, &back); err != nil || !reflect.DeepEqual(back, tt.item) {
+			t.Errorf("Unmarshal was incorrect, Actual: %+v %v, Expected: %+v", back, err, tt.item)
+		}
+	}
+}
+
+func TestEditSameDue(t *testing.T) {
+	l := testList(t)
+	// the same moment in another time zone isn't a change
+	l.Edit(1, func(item *Item) { item.Due = item.Due.In(time.FixedZone("CEST", 2*60*60)) })
+	if action, _ := l.Undo(); action != `add "pay rent"` {
+		t.Errorf("Edit was incorrect, Actual: undid %q, Expected: no change to undo", action)
+	}
+}
+
+func TestPriorityText(t *testing.T) {
+	for _, p := range []Priority{Low, Normal, High} {
+		text, _ := p.MarshalText()
+		var back Priority
+		if err := back.UnmarshalText(text); err != nil || back != p {
+			t.Errorf("Priority %v was incorrect, Actual: %v %v", p, back, err)
+		}
+	}
+	if _, err := ParsePriority("urgent"); err == nil {
+		t.Errorf("ParsePriority(urgent) was incorrect, Actual: nil, Expected: error")
+	}
+}
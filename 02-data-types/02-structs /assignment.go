@@ -1,14 +1,20 @@
+//go:build ignore
+
+// Run with: go run assignment.go
 package main
 
 import (
 	"fmt"
+	"log"
+
+	"json-example/money"
 )
 
 type Row struct {
 	Title       string
 	Description string
 	Quantity    int
-	UnitPrice   float32
+	UnitPrice   money.Money
 }
 
 func main() {
@@ -16,24 +22,31 @@ func main() {
 		Title:       "LEGO set",
 		Description: "4000 pieces",
 		Quantity:    1,
-		UnitPrice:   600,
+		UnitPrice:   money.MustParse("600.00 USD"),
 	}
 	row2 := Row{
 		Title:       "Plushy",
 		Description: "plush toy",
 		Quantity:    3,
-		UnitPrice:   5,
+		UnitPrice:   money.MustParse("4.99 USD"),
 	}
 
 	basket := make([]Row, 0)
 	basket = append(basket, row)
 	basket = append(basket, row2)
 
-	var sum int = 0
+	sum := money.New(0, "USD")
 	for i := 0; i < len(basket); i++ {
 		current := basket[i]
 		fmt.Println(current)
-		sum += current.Quantity * int(current.UnitPrice)
+		rowTotal, err := current.UnitPrice.Mul(int64(current.Quantity))
+		if err != nil {
+			log.Fatal(err)
+		}
+		sum, err = sum.Add(rowTotal)
+		if err != nil {
+			log.Fatal(err)
+		}
 	}
 	fmt.Println("Total", sum)
 }
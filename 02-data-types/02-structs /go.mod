@@ -1,3 +1,12 @@
 module struct
 
-go 1.16
+go 1.21
+
+require json-example v0.0.0
+
+require strings-example v0.0.0 // indirect
+
+replace (
+	json-example => ../../04-webdev/01-json
+	strings-example => ../../05-misc/02-strings
+)
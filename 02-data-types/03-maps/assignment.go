@@ -1,36 +1,228 @@
+//go:build ignore
+
+// Run with: go run assignment.go [-file phonebook.json] [-history file] [script]
 package main
 
-import "fmt"
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"input/repl"
+	"maps-example/phonebook"
+)
+
+func split(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
 
 func main() {
-	var command string
-	contacts := make(map[string]string)
-	fmt.Println("Welcome to your phonebook")
-
-	for {
-		fmt.Print("Command> ")
-		fmt.Scan(&command)
-		if command == "store" {
-			fmt.Print("Enter contact: ")
-			var contact string
-			var no string
-			fmt.Scan(&contact, &no)
-			contacts[contact] = no
-			fmt.Println("Contact saved")
-		} else if command == "list" {
-			for key, value := range contacts {
-				fmt.Println(key, value)
+	file := flag.String("file", "phonebook.json", "where the contacts are saved")
+	history := flag.String("history", "", "keep what you type in this file between runs, like ~/.phonebook_history")
+	flag.Parse()
+
+	book, err := phonebook.Open(*file)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	r := repl.New("Command> ")
+	r.HistoryFile = *history
+	r.OnError = func(err error) { report(r, book, err) }
+	addCommands(r, book)
+
+	if flag.NArg() > 0 {
+		err = r.RunFile(flag.Arg(0))
+	} else {
+		fmt.Println("Welcome to your phonebook, type help to see the commands")
+		err = r.Run()
+	}
+	if err != nil {
+		report(r, book, err)
+		os.Exit(1)
+	}
+	fmt.Println("Bye")
+}
+
+func addCommands(r *repl.REPL, book *phonebook.Book) {
+	// save runs a command, and saves the phonebook if it changed it
+	save := func(run func(args []string) error) func(args []string) error {
+		return func(args []string) error {
+			if err := run(args); err != nil {
+				return err
 			}
-		} else if command == "lookup" {
-			fmt.Print("Enter name: ")
-			var contact string
-			fmt.Scan(&contact)
-			fmt.Println(contacts[contact])
-		} else if command == "quit" {
-			break
+			return book.Save()
+		}
+	}
+	names := func(args []string) []string {
+		if len(args) > 0 {
+			return nil
+		}
+		var names []string
+		for _, c := range book.List() {
+			names = append(names, c.Name)
+		}
+		return names
+	}
+	files := func(args []string) []string {
+		if len(args) > 0 {
+			return nil
+		}
+		matches, _ := filepath.Glob("*.[cv][sc][vf]")
+		return matches
+	}
+	// ask asks for what wasn't given as an argument
+	ask := func(args []string, question string) (string, error) {
+		if len(args) > 0 {
+			return args[0], nil
+		}
+		return r.Ask(question)
+	}
+
+	r.Add(repl.Command{Name: "store", Args: "[name] [numbers...]", Help: "add a contact, or replace one", Run: save(func(args []string) error {
+		name, err := ask(args, "Name: ")
+		if err != nil {
+			return err
+		}
+		c := phonebook.Contact{Name: name}
+		if len(args) > 1 {
+			c.Numbers = args[1:]
 		} else {
-			fmt.Println("Unknown command: ", command)
+			answers := make([]string, 3)
+			for i, question := range []string{"Numbers, separated by commas: ", "Email: ", "Tags, separated by commas: "} {
+				if answers[i], err = r.Ask(question); err != nil {
+					return err
+				}
+			}
+			c.Numbers, c.Email, c.Tags = split(answers[0]), answers[1], split(answers[2])
+		}
+		if err := book.Store(c); err != nil {
+			return err
+		}
+		r.Println("Contact saved")
+		return nil
+	})})
+	r.Add(repl.Command{Name: "list", Help: "list the contacts by name", Run: func([]string) error {
+		for _, c := range book.List() {
+			show(r, c)
+		}
+		return nil
+	}})
+	r.Add(repl.Command{Name: "lookup", Args: "[name]", Help: "find contacts by name, or the start of it", Complete: names, Run: func(args []string) error {
+		name, err := ask(args, "Enter name: ")
+		if err != nil {
+			return err
+		}
+		found, err := book.Lookup(name)
+		if err != nil {
+			return err
+		}
+		for _, c := range found {
+			show(r, c)
+		}
+		return nil
+	}})
+	r.Add(repl.Command{Name: "delete", Args: "[name]", Help: "delete a contact", Complete: names, Run: save(func(args []string) error {
+		name, err := ask(args, "Enter name: ")
+		if err != nil {
+			return err
+		}
+		if err := book.Delete(name); err != nil {
+			return err
+		}
+		r.Println("Contact deleted")
+		return nil
+	})})
+	r.Add(repl.Command{Name: "edit", Args: "[name]", Help: "change a contact", Complete: names, Run: save(func(args []string) error {
+		name, err := ask(args, "Enter name: ")
+		if err != nil {
+			return err
+		}
+		var askErr error
+		err = book.Edit(name, func(c *phonebook.Contact) {
+			r.Println("Press enter to keep what's in brackets, type - to clear it")
+			edit := func(question, old string) string {
+				answer, err := r.Ask(fmt.Sprintf("%s [%s]: ", question, old))
+				if err != nil {
+					askErr = err
+				}
+				switch answer {
+				case "":
+					return old
+				case "-":
+					return ""
+				default:
+					return answer
+				}
+			}
+			c.Name = edit("Name", c.Name)
+			c.Numbers = split(edit("Numbers", strings.Join(c.Numbers, ",")))
+			c.Email = edit("Email", c.Email)
+			c.Tags = split(edit("Tags", strings.Join(c.Tags, ",")))
+		})
+		if err := errors.Join(askErr, err); err != nil {
+			return err
+		}
+		r.Println("Contact saved")
+		return nil
+	})})
+	r.Add(repl.Command{Name: "import", Args: "<file>", Help: "add the contacts in a .vcf or .csv file", Complete: files, Run: save(func(args []string) error {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
 		}
+		defer f.Close()
+		contacts, err := phonebook.ReadFile(args[0], f)
+		if err != nil {
+			return err
+		}
+		added, updated, err := book.Import(contacts)
+		if err != nil {
+			return err
+		}
+		r.Printf("Added %d contacts, updated %d\n", added, updated)
+		return nil
+	})})
+	r.Add(repl.Command{Name: "export", Args: "<file>", Help: "write the contacts to a .vcf or .csv file", Complete: files, Run: func(args []string) error {
+		if err := book.Export(args[0]); err != nil {
+			return err
+		}
+		r.Printf("Exported %d contacts to %s\n", book.Len(), args[0])
+		return nil
+	}})
+}
+
+func show(r *repl.REPL, c phonebook.Contact) {
+	line := c.Name + "\t" + strings.Join(c.Numbers, ", ")
+	if c.Email != "" {
+		line += "\t" + c.Email
 	}
-	fmt.Println("Bye")
+	if len(c.Tags) > 0 {
+		line += "\t[" + strings.Join(c.Tags, ", ") + "]"
+	}
+	r.Println(line)
+}
+
+func report(r *repl.REPL, book *phonebook.Book, err error) {
+	var script *repl.ScriptError
+	if errors.As(err, &script) {
+		r.Printf("Line %d: %s\n", script.Line, script.Text)
+		err = script.Err
+	}
+	var notFound *phonebook.NotFoundError
+	if errors.As(err, &notFound) {
+		r.Printf("No contact named %q\n", notFound.Name)
+		if names := book.Suggest(notFound.Name); len(names) > 0 {
+			r.Printf("Did you mean %s?\n", strings.Join(names, " or "))
+		}
+		return
+	}
+	r.Println(err)
 }
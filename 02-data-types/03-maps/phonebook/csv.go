@@ -0,0 +1,114 @@
+package phonebook
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// listSeparator separates numbers and tags in a CSV cell.
+const listSeparator = ";"
+
+// WriteCSV writes contacts as CSV, with a header. Numbers and tags are
+// separated by semicolons.
+func WriteCSV(w io.Writer, contacts []Contact) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"name", "numbers", "email", "tags"})
+	for _, c := range contacts {
+		cw.Write([]string{c.Name, strings.Join(c.Numbers, listSeparator), c.Email, strings.Join(c.Tags, listSeparator)})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadCSV reads contacts from CSV. The first row names the columns, in
+// any order; a name column is required, others are optional.
+func ReadCSV(r io.Reader) ([]Contact, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := map[string]int{}
+	for i, h := range header {
+		columns[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, errors.New("the CSV file has no name column")
+	}
+
+	var contacts []Contact
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return contacts, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		get := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(row) {
+				return row[i]
+			}
+			return ""
+		}
+		contacts = append(contacts, clean(Contact{
+			Name:    get("name"),
+			Numbers: strings.Split(get("numbers"), listSeparator),
+			Email:   get("email"),
+			Tags:    strings.Split(get("tags"), listSeparator),
+		}))
+	}
+}
+
+// ReadFile reads contacts from r in the format named by the extension of
+// path: .vcf or .vcard for vCard, .csv for CSV.
+func ReadFile(path string, r io.Reader) ([]Contact, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".vcf", ".vcard":
+		return ReadVCard(r)
+	case ".csv":
+		return ReadCSV(r)
+	}
+	return nil, fmt.Errorf("%s: unknown file type, use .vcf or .csv", path)
+}
+
+// WriteFile writes contacts to w in the format named by the extension of path.
+func WriteFile(path string, w io.Writer, contacts []Contact) error {
+	write, err := writerFor(path)
+	if err != nil {
+		return err
+	}
+	return write(w, contacts)
+}
+
+// Export writes the contacts to path as vCard or CSV, depending on its
+// extension. Like Save it writes a new file and renames it, so nothing is
+// overwritten when the extension is wrong or writing fails.
+func (b *Book) Export(path string) error {
+	write, err := writerFor(path)
+	if err != nil {
+		return err
+	}
+	contacts := b.List()
+	return writeFile(path, func(w io.Writer) error {
+		return write(w, contacts)
+	})
+}
+
+func writerFor(path string) (func(io.Writer, []Contact) error, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".vcf", ".vcard":
+		return WriteVCard, nil
+	case ".csv":
+		return WriteCSV, nil
+	}
+	return nil, fmt.Errorf("%s: unknown file type, use .vcf or .csv", path)
+}
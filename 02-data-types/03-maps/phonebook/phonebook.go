@@ -0,0 +1,288 @@
+// Package phonebook keeps contacts in a map and saves them to a JSON file,
+// so they are still there the next time the program runs.
+package phonebook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"strings-example/textutil"
+)
+
+var (
+	ErrNotFound = errors.New("no such contact")
+	ErrExists   = errors.New("there's already a contact with that name")
+	ErrNoName   = errors.New("a contact needs a name")
+)
+
+// NotFoundError says which contact wasn't found. It is ErrNotFound for errors.Is.
+type NotFoundError struct {
+	Name string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("no contact named %q", e.Name)
+}
+
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// Contact is a person in the phonebook.
+type Contact struct {
+	Name    string   `json:"name"`
+	Numbers []string `json:"numbers,omitempty"`
+	Email   string   `json:"email,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+}
+
+// Book is a phonebook. Names are unique, ignoring case.
+type Book struct {
+	path     string
+	contacts map[string]Contact
+}
+
+// New returns an empty phonebook that isn't saved anywhere.
+func New() *Book {
+	return &Book{contacts: map[string]Contact{}}
+}
+
+// Open reads the phonebook saved in path. A file that doesn't exist yet
+// gives an empty phonebook, Save creates it.
+func Open(path string) (*Book, error) {
+	b := New()
+	b.path = path
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return b, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var contacts []Contact
+	if err := json.Unmarshal(data, &contacts); err != nil {
+		return nil, fmt.Errorf("the phonebook file %s is broken: %w", path, err)
+	}
+	for _, c := range contacts {
+		b.contacts[key(c.Name)] = c
+	}
+	return b, nil
+}
+
+// Save writes the phonebook to the file it was opened from. It writes a
+// new file and renames it, so a crash can't leave half a phonebook behind.
+func (b *Book) Save() error {
+	if b.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(b.List(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(b.path, func(w io.Writer) error {
+		_, err := w.Write(append(data, '\n'))
+		return err
+	})
+}
+
+// writeFile writes path through a temporary file in the same directory,
+// which is renamed to path once write succeeded.
+func writeFile(path string, write func(w io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".phonebook-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func key(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Len returns the number of contacts.
+func (b *Book) Len() int {
+	return len(b.contacts)
+}
+
+// Store adds c, or replaces the contact with the same name.
+func (b *Book) Store(c Contact) error {
+	c = clean(c)
+	if c.Name == "" {
+		return ErrNoName
+	}
+	b.contacts[key(c.Name)] = c
+	return nil
+}
+
+// Get returns the contact called name, ignoring case.
+func (b *Book) Get(name string) (Contact, error) {
+	c, ok := b.contacts[key(name)]
+	if !ok {
+		return Contact{}, &NotFoundError{Name: name}
+	}
+	return c, nil
+}
+
+// Delete removes the contact called name.
+func (b *Book) Delete(name string) error {
+	if _, ok := b.contacts[key(name)]; !ok {
+		return &NotFoundError{Name: name}
+	}
+	delete(b.contacts, key(name))
+	return nil
+}
+
+// Edit changes the contact called name with change. Change the name and
+// the contact is renamed, unless another contact already has that name.
+func (b *Book) Edit(name string, change func(c *Contact)) error {
+	c, err := b.Get(name)
+	if err != nil {
+		return err
+	}
+	c.Numbers = append([]string(nil), c.Numbers...)
+	c.Tags = append([]string(nil), c.Tags...)
+	change(&c)
+	c = clean(c)
+	if c.Name == "" {
+		return ErrNoName
+	}
+	if key(c.Name) != key(name) {
+		if _, ok := b.contacts[key(c.Name)]; ok {
+			return fmt.Errorf("%w: %q", ErrExists, c.Name)
+		}
+		delete(b.contacts, key(name))
+	}
+	b.contacts[key(c.Name)] = c
+	return nil
+}
+
+// List returns all contacts sorted by name.
+func (b *Book) List() []Contact {
+	list := make([]Contact, 0, len(b.contacts))
+	for _, c := range b.contacts {
+		list = append(list, c)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return key(list[i].Name) < key(list[j].Name)
+	})
+	return list
+}
+
+// Lookup finds contacts by name. A name that matches exactly gives that
+// contact. Otherwise it returns the contacts whose name starts with query,
+// and if there are none, the ones that have its letters in order, so "jdoe"
+// finds "John Doe". Nothing found is a *NotFoundError.
+func (b *Book) Lookup(query string) ([]Contact, error) {
+	if c, err := b.Get(query); err == nil {
+		return []Contact{c}, nil
+	}
+	var found []Contact
+	for _, c := range b.List() {
+		if strings.HasPrefix(key(c.Name), key(query)) {
+			found = append(found, c)
+		}
+	}
+	if len(found) > 0 {
+		return found, nil
+	}
+	for _, m := range textutil.Fuzzy(strings.TrimSpace(query), b.names()) {
+		found = append(found, b.contacts[key(m.Text)])
+	}
+	if len(found) == 0 {
+		return nil, &NotFoundError{Name: query}
+	}
+	return found, nil
+}
+
+// Suggest returns names that look like name, for when it has a typo.
+func (b *Book) Suggest(name string) []string {
+	return textutil.Closest(strings.TrimSpace(name), b.names(), 2)
+}
+
+func (b *Book) names() []string {
+	var names []string
+	for _, c := range b.List() {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+// Import stores contacts. A contact that already exists gets the numbers
+// and tags it didn't have yet, and the email if it had none. If a contact
+// has no name, nothing is imported.
+func (b *Book) Import(contacts []Contact) (added, updated int, err error) {
+	for i, c := range contacts {
+		if clean(c).Name == "" {
+			return 0, 0, fmt.Errorf("contact %d: %w", i+1, ErrNoName)
+		}
+	}
+	for _, c := range contacts {
+		c = clean(c)
+		old, ok := b.contacts[key(c.Name)]
+		if !ok {
+			b.contacts[key(c.Name)] = c
+			added++
+			continue
+		}
+		old.Numbers = merge(old.Numbers, c.Numbers)
+		old.Tags = merge(old.Tags, c.Tags)
+		if old.Email == "" {
+			old.Email = c.Email
+		}
+		b.contacts[key(c.Name)] = old
+		updated++
+	}
+	return added, updated, nil
+}
+
+func merge(list, more []string) []string {
+	for _, s := range more {
+		if !contains(list, s) {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if strings.EqualFold(x, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// clean trims spaces and drops empty and repeated numbers and tags.
+func clean(c Contact) Contact {
+	c.Name = strings.Join(strings.Fields(c.Name), " ")
+	c.Email = strings.TrimSpace(c.Email)
+	c.Numbers = cleanList(c.Numbers)
+	c.Tags = cleanList(c.Tags)
+	return c
+}
+
+func cleanList(list []string) []string {
+	var out []string
+	for _, s := range list {
+		if s = strings.TrimSpace(s); s != "" && !contains(out, s) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,306 @@
+package phonebook
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testBook(t *testing.T) *Book {
+	t.Helper()
+	b := New()
+	for _, c := range []Contact{
+		{Name: "Jane Doe", Numbers: []string{"555-1234"}, Email: "jane@example.com", Tags: []string{"work"}},
+		{Name: "John Smith", Numbers: []string{"555-0000"}},
+		{Name: "Åsa Lindqvist", Numbers: []string{"+46 40 123"}},
+	} {
+		if err := b.Store(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return b
+}
+
+func names(contacts []Contact) []string {
+	var list []string
+	for _, c := range contacts {
+		list = append(list, c.Name)
+	}
+	return list
+}
+
+func TestStoreCleans(t *testing.T) {
+	b := New()
+	b.Store(Contact{Name: "  Jane   Doe ", Numbers: []string{" 1 ", "", "1", "2"}})
+	got, err := b.Get("jane doe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Contact{Name: "Jane Doe", Numbers: []string{"1", "2"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Store was incorrect, Actual: %+v, Expected: %+v", got, want)
+	}
+	if err := b.Store(Contact{Name: " "}); !errors.Is(err, ErrNoName) {
+		t.Errorf("Store without a name was incorrect, Actual: %v, Expected: %v", err, ErrNoName)
+	}
+}
+
+func TestList(t *testing.T) {
+	got := names(testBook(t).List())
+	// Å sorts after the ASCII letters
+	want := []string{"Jane Doe", "John Smith", "Åsa Lindqvist"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("List was incorrect, Actual: %v, Expected: %v", got, want)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	b := testBook(t)
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"jane doe", []string{"Jane Doe"}},
+		{"j", []string{"Jane Doe", "John Smith"}},
+		{"åsa", []string{"Åsa Lindqvist"}},
+		{"jsmith", []string{"John Smith"}},
+	}
+	for _, tt := range tests {
+		found, err := b.Lookup(tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := names(found); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Lookup(%q) was incorrect, Actual: %v, Expected: %v", tt.query, got, tt.want)
+		}
+	}
+
+	_, err := b.Lookup("xyz")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Lookup(xyz) was incorrect, Actual: %v, Expected: %v", err, ErrNotFound)
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	b := testBook(t)
+	_, getErr := b.Get("Nobody")
+	for _, err := range []error{getErr, b.Delete("Nobody"), b.Edit("Nobody", func(*Contact) {})} {
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("was incorrect, Actual: %v, Expected: %v", err, ErrNotFound)
+			continue
+		}
+		var nf *NotFoundError
+		if !errors.As(err, &nf) || nf.Name != "Nobody" {
+			t.Errorf("NotFoundError was incorrect, Actual: %v, Expected: Nobody", err)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	got := testBook(t).Suggest("Jane Do")
+	if want := []string{"Jane Doe"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Suggest was incorrect, Actual: %v, Expected: %v", got, want)
+	}
+}
+
+func TestEdit(t *testing.T) {
+	b := testBook(t)
+	err := b.Edit("jane doe", func(c *Contact) {
+		c.Name = "Jane Roe"
+		c.Numbers = append(c.Numbers, "555-4321")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Get("Jane Doe"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("old name was incorrect, Actual: %v, Expected: %v", err, ErrNotFound)
+	}
+	c, _ := b.Get("Jane Roe")
+	if want := []string{"555-1234", "555-4321"}; !reflect.DeepEqual(c.Numbers, want) {
+		t.Errorf("Edit was incorrect, Actual: %v, Expected: %v", c.Numbers, want)
+	}
+
+	err = b.Edit("Jane Roe", func(c *Contact) { c.Name = "john smith" })
+	if !errors.Is(err, ErrExists) {
+		t.Errorf("renaming to an existing name was incorrect, Actual: %v, Expected: %v", err, ErrExists)
+	}
+	if b.Len() != 3 {
+		t.Errorf("Len was incorrect, Actual: %d, Expected: 3", b.Len())
+	}
+}
+
+func TestEditDoesNotChangeOnError(t *testing.T) {
+	b := testBook(t)
+	b.Edit("Jane Doe", func(c *Contact) {
+		c.Numbers[0] = "changed"
+		c.Name = ""
+	})
+	c, _ := b.Get("Jane Doe")
+	if c.Numbers[0] != "555-1234" {
+		t.Errorf("Edit was incorrect, Actual: %v, Expected: 555-1234", c.Numbers[0])
+	}
+}
+
+func TestSaveAndOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "phonebook.json")
+	b, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Len() != 0 {
+		t.Errorf("Open of a new file was incorrect, Actual: %d contacts, Expected: 0", b.Len())
+	}
+	for _, c := range testBook(t).List() {
+		b.Store(c)
+	}
+	if err := b.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	again, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again.List(), b.List()) {
+		t.Errorf("Open was incorrect, Actual: %v, Expected: %v", again.List(), b.List())
+	}
+}
+
+func TestImportMerges(t *testing.T) {
+	b := testBook(t)
+	added, updated, err := b.Import([]Contact{
+		{Name: "jane doe", Numbers: []string{"555-1234", "555-9999"}, Email: "other@example.com", Tags: []string{"Work", "golf"}},
+		{Name: "New Person"},
+	})
+	if err != nil || added != 1 || updated != 1 {
+		t.Errorf("Import was incorrect, Actual: %d %d %v, Expected: 1 1 <nil>", added, updated, err)
+	}
+	c, _ := b.Get("Jane Doe")
+	want := Contact{Name: "Jane Doe", Numbers: []string{"555-1234", "555-9999"}, Email: "jane@example.com", Tags: []string{"work", "golf"}}
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("Import was incorrect, Actual: %+v, Expected: %+v", c, want)
+	}
+}
+
+func TestImportWithoutName(t *testing.T) {
+	b := testBook(t)
+	_, _, err := b.Import([]Contact{
+		{Name: "Jane Doe", Numbers: []string{"555-9999"}},
+		{Name: "New Person"},
+		{Name: " "},
+	})
+	if !errors.Is(err, ErrNoName) {
+		t.Errorf("Import was incorrect, Actual: %v, Expected: %v", err, ErrNoName)
+	}
+	c, _ := b.Get("Jane Doe")
+	if b.Len() != 3 || len(c.Numbers) != 1 {
+		t.Errorf("Import should change nothing, Actual: %d contacts, %v", b.Len(), c.Numbers)
+	}
+}
+
+func TestWriteVCardName(t *testing.T) {
+	var sb strings.Builder
+	WriteVCard(&sb, []Contact{{Name: "Jane Mary Doe"}, {Name: "Cher"}})
+	for _, want := range []string{"N:Doe;Jane Mary;;;\r\n", "N:Cher;;;;\r\n"} {
+		if !strings.Contains(sb.String(), want) {
+			t.Errorf("WriteVCard was incorrect, Actual: %q, Expected: %q", sb.String(), want)
+		}
+	}
+}
+
+func TestFormatsRoundTrip(t *testing.T) {
+	contacts := []Contact{
+		{Name: "Doe, Jane; the 2nd", Numbers: []string{"555-1234", "555-9999"}, Email: "jane@example.com", Tags: []string{"work", "a,b"}},
+		{Name: "Åsa Lindqvist", Numbers: []string{"+46 40 123"}},
+	}
+	for _, path := range []string{"contacts.vcf", "contacts.csv"} {
+		var sb strings.Builder
+		if err := WriteFile(path, &sb, contacts); err != nil {
+			t.Fatal(err)
+		}
+		got, err := ReadFile(path, strings.NewReader(sb.String()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, contacts) {
+			t.Errorf("%s was incorrect, Actual: %+v, Expected: %+v", path, got, contacts)
+		}
+	}
+}
+
+func TestExportUnknownType(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "phonebook.json")
+	if err := os.WriteFile(path, []byte("[]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := testBook(t).Export(path); err == nil {
+		t.Errorf("Export to a .json file was incorrect, Actual: nil, Expected: an error")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "[]\n" {
+		t.Errorf("Export changed the file, Actual: %q, Expected: %q", data, "[]\n")
+	}
+
+	csvPath := filepath.Join(filepath.Dir(path), "contacts.csv")
+	if err := testBook(t).Export(csvPath); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(csvPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	got, err := ReadCSV(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := testBook(t).List(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Export was incorrect, Actual: %+v, Expected: %+v", got, want)
+	}
+}
+
+func TestReadVCard(t *testing.T) {
+	vcard := "BEGIN:VCARD\r\n" +
+		"VERSION:3.0\r\n" +
+		"N:Lindqvist;Åsa;;;\r\n" +
+		"item1.TEL;TYPE=cell:+46 40\r\n" +
+		" 123\r\n" +
+		"EMAIL;TYPE=home:asa@example.se\r\n" +
+		"PHOTO:ignored\r\n" +
+		"END:VCARD\r\n"
+	got, err := ReadVCard(strings.NewReader(vcard))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Contact{{Name: "Åsa Lindqvist", Numbers: []string{"+46 40123"}, Email: "asa@example.se"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadVCard was incorrect, Actual: %+v, Expected: %+v", got, want)
+	}
+
+	if _, err := ReadVCard(strings.NewReader("BEGIN:VCARD\nFN:Jane\n")); err == nil {
+		t.Errorf("ReadVCard without END was incorrect, Actual: nil, Expected: error")
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	got, err := ReadCSV(strings.NewReader("Email,Name\njane@example.com,Jane Doe\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Contact{{Name: "Jane Doe", Email: "jane@example.com"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadCSV was incorrect, Actual: %+v, Expected: %+v", got, want)
+	}
+	if _, err := ReadCSV(strings.NewReader("phone\n123\n")); err == nil {
+		t.Errorf("ReadCSV without a name column was incorrect, Actual: nil, Expected: error")
+	}
+	if _, err := ReadFile("contacts.txt", strings.NewReader("")); err == nil {
+		t.Errorf("ReadFile(.txt) was incorrect, Actual: nil, Expected: error")
+	}
+}
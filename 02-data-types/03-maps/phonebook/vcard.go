@@ -0,0 +1,155 @@
+package phonebook
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var vcardEscaper = strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\n", `\n`)
+
+// WriteVCard writes contacts as vCard 3.0, the format phones and mail
+// programs import.
+func WriteVCard(w io.Writer, contacts []Contact) error {
+	bw := bufio.NewWriter(w)
+	for _, c := range contacts {
+		fmt.Fprint(bw, "BEGIN:VCARD\r\nVERSION:3.0\r\n")
+		fmt.Fprintf(bw, "FN:%s\r\n", vcardEscaper.Replace(c.Name))
+		family, given := splitName(c.Name)
+		fmt.Fprintf(bw, "N:%s;%s;;;\r\n", vcardEscaper.Replace(family), vcardEscaper.Replace(given))
+		for _, no := range c.Numbers {
+			fmt.Fprintf(bw, "TEL:%s\r\n", vcardEscaper.Replace(no))
+		}
+		if c.Email != "" {
+			fmt.Fprintf(bw, "EMAIL:%s\r\n", vcardEscaper.Replace(c.Email))
+		}
+		if len(c.Tags) > 0 {
+			tags := make([]string, len(c.Tags))
+			for i, t := range c.Tags {
+				tags[i] = vcardEscaper.Replace(t)
+			}
+			fmt.Fprintf(bw, "CATEGORIES:%s\r\n", strings.Join(tags, ","))
+		}
+		fmt.Fprint(bw, "END:VCARD\r\n")
+	}
+	return bw.Flush()
+}
+
+// splitName guesses the family and given names, taking the last word as
+// the family name: "Jane Doe" is Doe and Jane.
+func splitName(name string) (family, given string) {
+	i := strings.LastIndex(name, " ")
+	if i < 0 {
+		return name, ""
+	}
+	return name[i+1:], name[:i]
+}
+
+// ReadVCard reads the contacts in a vCard file. It understands the
+// properties WriteVCard writes, with or without parameters like
+// TEL;TYPE=cell, and skips the rest.
+func ReadVCard(r io.Reader) ([]Contact, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var contacts []Contact
+	var c *Contact
+	var family string
+	for i, line := range lines {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		// drop parameters and groups, "item1.TEL;TYPE=cell" is TEL
+		name, _, _ = strings.Cut(strings.ToUpper(name), ";")
+		if _, after, ok := strings.Cut(name, "."); ok {
+			name = after
+		}
+
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VCARD"):
+			c, family = &Contact{}, ""
+		case c == nil:
+			return nil, fmt.Errorf("line %d: not a vCard", i+1)
+		case name == "END":
+			if c.Name == "" {
+				c.Name = family
+			}
+			contacts = append(contacts, *c)
+			c = nil
+		case name == "FN":
+			c.Name = unescape(value)
+		case name == "N":
+			// N is family;given;additional;prefix;suffix
+			parts := splitEscaped(value, ';')
+			for len(parts) < 2 {
+				parts = append(parts, "")
+			}
+			family = strings.TrimSpace(parts[1] + " " + parts[0])
+		case name == "TEL":
+			c.Numbers = append(c.Numbers, strings.TrimPrefix(unescape(value), "tel:"))
+		case name == "EMAIL":
+			if c.Email == "" {
+				c.Email = unescape(value)
+			}
+		case name == "CATEGORIES":
+			c.Tags = append(c.Tags, splitEscaped(value, ',')...)
+		}
+	}
+	if c != nil {
+		return nil, fmt.Errorf("the vCard of %q has no END:VCARD", c.Name)
+	}
+	return contacts, nil
+}
+
+// unfold reads the lines of r, joining lines that start with a space or a
+// tab to the line before, the way long vCard lines are folded.
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimRight(s.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, s.Err()
+}
+
+// splitEscaped splits s on sep, except where sep is escaped with \.
+func splitEscaped(s string, sep byte) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case sep:
+			parts = append(parts, unescape(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(parts, unescape(s[start:]))
+}
+
+func unescape(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			if s[i] == 'n' || s[i] == 'N' {
+				sb.WriteByte('\n')
+				continue
+			}
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
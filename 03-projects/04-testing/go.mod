@@ -1,3 +1,7 @@
 module test-example
 
-go 1.16
+go 1.21
+
+require error-handling/errs v0.0.0
+
+replace error-handling/errs => ../../01-basics/08-error-handling/errs
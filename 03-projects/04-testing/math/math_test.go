@@ -1,6 +1,7 @@
 package math
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -19,3 +20,15 @@ func TestSub(t *testing.T) {
 	}
 	t.Log("running TestSubtract")
 }
+
+func TestSafeDivide(t *testing.T) {
+	total, err := SafeDivide(10, 4)
+	if err != nil || total != 2.5 {
+		t.Errorf("SafeDivide was incorrect, Actual: %v %v, Expected: %v", total, err, 2.5)
+	}
+
+	_, err = SafeDivide(10, 0)
+	if !errors.Is(err, ErrDivideByZero) {
+		t.Errorf("SafeDivide by zero was incorrect, Actual: %v, Expected: %v", err, ErrDivideByZero)
+	}
+}
@@ -0,0 +1,13 @@
+package math
+
+import "error-handling/errs"
+
+var ErrDivideByZero = errs.New(errs.Invalid, "can't divide by zero")
+
+// SafeDivide is Divide, but returns ErrDivideByZero instead of dividing by zero.
+func SafeDivide(lhs float32, rhs float32) (float32, error) {
+	if rhs == 0 {
+		return 0, ErrDivideByZero.With("lhs", lhs)
+	}
+	return Divide(lhs, rhs), nil
+}
@@ -0,0 +1,134 @@
+// Command jsonschema generates JSON Schema for the models in this chapter
+// and validates data files against it.
+//
+// Usage:
+//
+//	jsonschema generate person|orders|products
+//	jsonschema validate [-type person|orders|products] file...
+//
+// Without -type, the type is picked from the file name, orders.json is
+// validated as orders.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"json-example/model"
+	"json-example/schema"
+)
+
+// types maps the names used on the command line to the structs they describe.
+var types = map[string]interface{}{
+	"person":   model.Person{},
+	"orders":   model.Response{},
+	"products": model.Products{},
+}
+
+func typeNames() string {
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: jsonschema generate <type>")
+	fmt.Fprintln(os.Stderr, "       jsonschema validate [-type <type>] file...")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "types:", typeNames())
+}
+
+func schemaFor(name string) (*schema.Schema, error) {
+	v, ok := types[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown type %q, use one of %s", name, typeNames())
+	}
+	return schema.Generate(v)
+}
+
+func generate(args []string) error {
+	if len(args) != 1 {
+		usage()
+		os.Exit(2)
+	}
+	s, err := schemaFor(args[0])
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// validate returns the number of files with violations.
+func validate(args []string) (int, error) {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	typeName := flags.String("type", "", "type of the files: "+typeNames())
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	invalid := 0
+	for _, path := range flags.Args() {
+		name := *typeName
+		if name == "" {
+			name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		}
+		s, err := schemaFor(name)
+		if err != nil {
+			return invalid, fmt.Errorf("%s: %w", path, err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return invalid, err
+		}
+		violations, err := schema.Validate(s, data)
+		if err != nil {
+			return invalid, fmt.Errorf("%s: %w", path, err)
+		}
+		for _, v := range violations {
+			fmt.Printf("%s: %s\n", path, v)
+		}
+		if len(violations) > 0 {
+			invalid++
+		}
+	}
+	return invalid, nil
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "generate":
+		if err := generate(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "jsonschema:", err)
+			os.Exit(1)
+		}
+	case "validate":
+		invalid, err := validate(os.Args[2:])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "jsonschema:", err)
+			os.Exit(2)
+		}
+		if invalid > 0 {
+			os.Exit(1)
+		}
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
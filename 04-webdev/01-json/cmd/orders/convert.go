@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"io"
+)
+
+func convert(args []string) error {
+	flags := flag.NewFlagSet("convert", flag.ExitOnError)
+	from := flags.String("from", "", "input format, json or jsonl")
+	to := flags.String("to", "", "output format, json or jsonl")
+	flags.Parse(args)
+
+	in, out := flags.Arg(0), flags.Arg(1)
+	if *to == "" && (out == "" || out == "-") {
+		// converting to stdout, flip the input format by default
+		*to = formatLines
+		if f, _ := detectFormat(*from, in); f == formatLines {
+			*to = formatJSON
+		}
+	}
+
+	r, rc, err := openOrders(in, *from)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	w, wc, err := createOrders(out, *to)
+	if err != nil {
+		return err
+	}
+	defer wc.Close()
+
+	for {
+		order, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err := w.Write(order); err != nil {
+			return err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return wc.Close()
+}
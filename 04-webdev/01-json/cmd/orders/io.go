@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"json-example/stream"
+)
+
+const (
+	formatJSON  = "json"
+	formatLines = "jsonl"
+)
+
+// detectFormat picks the format from the file extension unless one was given.
+func detectFormat(format string, path string) (string, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".jsonl", ".ndjson":
+			return formatLines, nil
+		}
+		return formatJSON, nil
+	}
+	if format != formatJSON && format != formatLines {
+		return "", fmt.Errorf("unknown format %q, use %s or %s", format, formatJSON, formatLines)
+	}
+	return format, nil
+}
+
+// openOrders opens path, or stdin for "" and "-", as an order reader.
+func openOrders(path string, format string) (stream.OrderReader, io.Closer, error) {
+	format, err := detectFormat(format, path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var r io.ReadCloser = os.Stdin
+	if path != "" && path != "-" {
+		r, err = os.Open(path)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if format == formatLines {
+		return stream.NewLinesReader(r), r, nil
+	}
+	return stream.NewReader(r), r, nil
+}
+
+// createOrders creates path, or uses stdout for "" and "-", as an order writer.
+func createOrders(path string, format string) (stream.OrderWriter, io.Closer, error) {
+	format, err := detectFormat(format, path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var w io.WriteCloser = nopCloser{os.Stdout}
+	if path != "" && path != "-" {
+		w, err = os.Create(path)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if format == formatLines {
+		return stream.NewLinesWriter(w), w, nil
+	}
+	return stream.NewWriter(w), w, nil
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
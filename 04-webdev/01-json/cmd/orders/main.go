@@ -0,0 +1,49 @@
+// Command orders works with order exports without loading them into memory.
+//
+// Usage:
+//
+//	orders convert [-from json|jsonl] [-to json|jsonl] [input] [output]
+//	orders report [-from json|jsonl] [-format table|csv|json] [-ids 1-10,15] [-top 5] [input]
+//
+// Input and output default to stdin and stdout, the format is picked from the
+// file extension when not given: .jsonl and .ndjson are JSON Lines.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: orders <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "commands:")
+	fmt.Fprintln(os.Stderr, "  convert   convert between orders.json and JSON Lines")
+	fmt.Fprintln(os.Stderr, "  report    summarize totals, top items and averages")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "convert":
+		err = convert(os.Args[2:])
+	case "report":
+		err = runReport(os.Args[2:])
+	case "help", "-h", "-help", "--help":
+		usage()
+		return
+	default:
+		fmt.Fprintln(os.Stderr, "orders: unknown command", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "orders:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"os"
+
+	"json-example/report"
+)
+
+func runReport(args []string) error {
+	flags := flag.NewFlagSet("report", flag.ExitOnError)
+	from := flags.String("from", "", "input format, json or jsonl")
+	format := flags.String("format", "table", "output format, table, csv or json")
+	ids := flags.String("ids", "", "order ids to include, e.g. 1-10,15,20-")
+	top := flags.Int("top", 5, "number of items to list by revenue, 0 lists all")
+	flags.Parse(args)
+
+	filter, err := report.ParseFilter(*ids)
+	if err != nil {
+		return err
+	}
+
+	r, rc, err := openOrders(flags.Arg(0), *from)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	builder := report.NewBuilder(filter, *top)
+	for {
+		order, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err := builder.Add(order); err != nil {
+			return err
+		}
+	}
+	return report.Write(os.Stdout, builder.Summary(), *format)
+}
@@ -0,0 +1,33 @@
+// Command tagcheck reports struct tags that encoding/json can't read.
+//
+// Usage:
+//
+//	go run ./cmd/tagcheck ./...
+//	go run ./cmd/tagcheck ../../06-io/fix
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"json-example/tagcheck"
+)
+
+func main() {
+	patterns := os.Args[1:]
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	findings, err := tagcheck.Check(patterns)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "tagcheck:", err)
+		os.Exit(2)
+	}
+	for _, f := range findings {
+		fmt.Fprintln(os.Stderr, f)
+	}
+	if len(findings) > 0 {
+		os.Exit(1)
+	}
+}
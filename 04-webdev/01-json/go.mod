@@ -0,0 +1,7 @@
+module json-example
+
+go 1.21
+
+require strings-example v0.0.0
+
+replace strings-example => ../../05-misc/02-strings
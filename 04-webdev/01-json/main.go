@@ -3,16 +3,13 @@ package main
 import (
 	"encoding/json"
 	"fmt"
-)
 
-type Person struct {
-	Name string `json: "name"`
-	Age  int    `json: "age"`
-}
+	"json-example/model"
+)
 
 func main() {
 	str := `{ "name": "chris", "age": 20 }`
-	person := Person{}
+	person := model.Person{}
 	json.Unmarshal([]byte(str), &person)
 	fmt.Println(person)
 }
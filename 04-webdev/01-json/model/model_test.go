@@ -0,0 +1,80 @@
+package model
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+
+	"json-example/money"
+)
+
+func roundTrip(t *testing.T, in interface{}, out interface{}) {
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("marshal %T: %v", in, err)
+	}
+	err = json.Unmarshal(data, out)
+	if err != nil {
+		t.Fatalf("unmarshal %s: %v", data, err)
+	}
+	got := reflect.ValueOf(out).Elem().Interface()
+	if !reflect.DeepEqual(in, got) {
+		t.Errorf("Round trip was lossy, Actual: %+v, Expected: %+v (json %s)", got, in, data)
+	}
+}
+
+func TestPersonRoundTrip(t *testing.T) {
+	roundTrip(t, Person{Name: "chris", Age: 20}, &Person{})
+	roundTrip(t, Person{}, &Person{})
+}
+
+func TestOrderRoundTrip(t *testing.T) {
+	in := Response{
+		Orders: []Order{
+			{Id: 1, Items: []OrderItem{{Id: 1, Quantity: 3, Total: money.New(3430, "")}, {Id: 2, Quantity: 2, Total: money.New(1780, "EUR")}}},
+			{Id: 2},
+		},
+	}
+	roundTrip(t, in, &Response{})
+}
+
+func TestProductRoundTrip(t *testing.T) {
+	in := Products{Products: []Product{{Id: 1, Name: "test"}, {Id: 2, Name: "test2"}}}
+	roundTrip(t, in, &Products{})
+}
+
+func TestPersonFile(t *testing.T) {
+	data, err := os.ReadFile("../person.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	person := Person{}
+	err = json.Unmarshal(data, &person)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Person{Name: "chris", Age: 20}
+	if person != expected {
+		t.Errorf("Person was incorrect, Actual: %+v, Expected: %+v", person, expected)
+	}
+}
+
+func TestOrdersFile(t *testing.T) {
+	data, err := os.ReadFile("../orders.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	response := Response{}
+	err = json.Unmarshal(data, &response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Orders) != 2 || len(response.Orders[1].Items) != 2 {
+		t.Fatalf("Orders were incorrect, Actual: %+v", response)
+	}
+	item := response.Orders[1].Items[1]
+	if item.Id != 4 || item.Quantity != 2 || item.Total != money.New(10050, "") {
+		t.Errorf("Item was incorrect, Actual: %+v, Expected: {Id:4 Quantity:2 Total:100.5}", item)
+	}
+}
@@ -0,0 +1,20 @@
+package model
+
+import "json-example/money"
+
+// OrderItem is a single line of an order.
+type OrderItem struct {
+	Id       int         `json:"id,omitempty"`
+	Quantity int         `json:"quantity,omitempty"`
+	Total    money.Money `json:"total"`
+}
+
+type Order struct {
+	Id    int         `json:"id,omitempty"`
+	Items []OrderItem `json:"items,omitempty"`
+}
+
+// Response matches the layout of orders.json.
+type Response struct {
+	Orders []Order `json:"orders,omitempty"`
+}
@@ -0,0 +1,7 @@
+package model
+
+// Person matches the data in person.json.
+type Person struct {
+	Name string `json:"name,omitempty"`
+	Age  int    `json:"age,omitempty"`
+}
@@ -0,0 +1,11 @@
+package model
+
+type Product struct {
+	Id   int    `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+// Products matches the layout of products.json.
+type Products struct {
+	Products []Product `json:"products,omitempty"`
+}
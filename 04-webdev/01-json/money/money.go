@@ -0,0 +1,327 @@
+// Package money provides a fixed-point type for amounts of money.
+//
+// Amounts are stored as an integer number of minor units (cents for USD),
+// so adding prices never loses precision the way float32 does.
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+var ErrDivideByZero = errors.New("divide by zero")
+var ErrOverflow = errors.New("amount out of range")
+
+type Money struct {
+	Amount   int64  // in minor units, e.g. cents
+	Currency string // ISO 4217 code, empty if unknown
+}
+
+// currencies whose minor unit isn't 1/100
+var minorDigits = map[string]int{
+	"BHD": 3,
+	"CLP": 0,
+	"ISK": 0,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"OMR": 3,
+	"VND": 0,
+}
+
+// Digits returns the number of decimals the currency uses, 2 unless known otherwise.
+func Digits(currency string) int {
+	if d, ok := minorDigits[currency]; ok {
+		return d
+	}
+	return 2
+}
+
+// New returns an amount given in minor units, New(1050, "USD") is 10.50 USD.
+func New(minor int64, currency string) Money {
+	return Money{Amount: minor, Currency: strings.ToUpper(currency)}
+}
+
+// Parse reads amounts like "34.3", "34.30 USD" or "USD 34.30".
+// Extra decimals are rounded half to even.
+func Parse(s string) (Money, error) {
+	fields := strings.Fields(s)
+	var amount, currency string
+	switch len(fields) {
+	case 1:
+		amount = fields[0]
+	case 2:
+		amount, currency = fields[0], fields[1]
+		if isCurrency(amount) {
+			amount, currency = currency, amount
+		}
+		if !isCurrency(currency) {
+			return Money{}, fmt.Errorf("money: invalid currency in %q", s)
+		}
+	default:
+		return Money{}, fmt.Errorf("money: can't parse %q", s)
+	}
+
+	currency = strings.ToUpper(currency)
+	minor, err := parseAmount(amount, Digits(currency))
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: minor, Currency: currency}, nil
+}
+
+// MustParse is like Parse but panics if s can't be parsed.
+func MustParse(s string) Money {
+	m, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func isCurrency(s string) bool {
+	if len(s) != 3 {
+		return false
+	}
+	for _, c := range s {
+		if !('A' <= c && c <= 'Z' || 'a' <= c && c <= 'z') {
+			return false
+		}
+	}
+	return true
+}
+
+// decimal is what an amount looks like: no fractions like 1/3, no exponents like 1e6.
+var decimal = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+
+// parseAmount converts a decimal number to minor units using exact arithmetic.
+func parseAmount(s string, digits int) (int64, error) {
+	if !decimal.MatchString(s) {
+		return 0, fmt.Errorf("money: invalid amount %q", s)
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return 0, fmt.Errorf("money: invalid amount %q", s)
+	}
+	r.Mul(r, new(big.Rat).SetInt(pow10(digits)))
+	minor := roundHalfEven(r.Num(), r.Denom())
+	if !minor.IsInt64() {
+		return 0, fmt.Errorf("money: %q: %w", s, ErrOverflow)
+	}
+	return minor.Int64(), nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// roundHalfEven returns num/den rounded to the nearest integer, ties go to the even one.
+func roundHalfEven(num, den *big.Int) *big.Int {
+	if den.Sign() < 0 {
+		num = new(big.Int).Neg(num)
+		den = new(big.Int).Neg(den)
+	}
+	q, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Sign() == 0 {
+		return q
+	}
+	twice := new(big.Int).Abs(rem)
+	twice.Lsh(twice, 1)
+	cmp := twice.Cmp(den)
+	if cmp > 0 || cmp == 0 && q.Bit(0) == 1 {
+		if num.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
+
+func (m Money) check(other Money) error {
+	if m.Currency != other.Currency {
+		return fmt.Errorf("%w: %q and %q", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return nil
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.check(other); err != nil {
+		return Money{}, err
+	}
+	sum := m.Amount + other.Amount
+	if (sum > m.Amount) != (other.Amount > 0) {
+		return Money{}, ErrOverflow
+	}
+	return Money{Amount: sum, Currency: m.Currency}, nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	return m.Add(other.Neg())
+}
+
+func (m Money) Neg() Money {
+	return Money{Amount: -m.Amount, Currency: m.Currency}
+}
+
+// Mul multiplies by a whole number, like a quantity.
+func (m Money) Mul(n int64) (Money, error) {
+	return m.Scale(n, 1)
+}
+
+// Div divides into n parts, rounding half to even.
+func (m Money) Div(n int64) (Money, error) {
+	return m.Scale(1, n)
+}
+
+// Scale multiplies by the fraction num/den, rounding half to even.
+// Scale(25, 100) is 25 percent of m.
+func (m Money) Scale(num, den int64) (Money, error) {
+	if den == 0 {
+		return Money{}, ErrDivideByZero
+	}
+	n := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(num))
+	result := roundHalfEven(n, big.NewInt(den))
+	if !result.IsInt64() {
+		return Money{}, ErrOverflow
+	}
+	return Money{Amount: result.Int64(), Currency: m.Currency}, nil
+}
+
+// Cmp returns -1, 0 or 1 depending on whether m is less than, equal to or greater than other.
+func (m Money) Cmp(other Money) (int, error) {
+	if err := m.check(other); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.Amount < other.Amount:
+		return -1, nil
+	case m.Amount > other.Amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// Sum adds all amounts, they must share the same currency.
+func Sum(amounts ...Money) (Money, error) {
+	if len(amounts) == 0 {
+		return Money{}, nil
+	}
+	total := amounts[0]
+	for _, m := range amounts[1:] {
+		var err error
+		total, err = total.Add(m)
+		if err != nil {
+			return Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// Decimal formats the amount without currency, like "34.30".
+func (m Money) Decimal() string {
+	digits := Digits(m.Currency)
+	sign := ""
+	amount := new(big.Int).SetInt64(m.Amount)
+	if amount.Sign() < 0 {
+		sign = "-"
+		amount.Neg(amount)
+	}
+	s := amount.String()
+	if digits == 0 {
+		return sign + s
+	}
+	if len(s) <= digits {
+		s = strings.Repeat("0", digits-len(s)+1) + s
+	}
+	return sign + s[:len(s)-digits] + "." + s[len(s)-digits:]
+}
+
+func (m Money) String() string {
+	if m.Currency == "" {
+		return m.Decimal()
+	}
+	return m.Decimal() + " " + m.Currency
+}
+
+// MarshalText writes the amount like String does, so Money works as XML
+// element content and with flag.TextVar.
+func (m Money) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText reads an amount like Parse does.
+func (m *Money) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// MarshalJSON writes a plain number when there's no currency, like 34.30,
+// and a string like "34.30 USD" otherwise.
+func (m Money) MarshalJSON() ([]byte, error) {
+	if m.Currency == "" {
+		return []byte(m.Decimal()), nil
+	}
+	return json.Marshal(m.String())
+}
+
+// UnmarshalJSON accepts a number, a string or an object with amount and currency.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = []byte(strings.TrimSpace(string(data)))
+	if string(data) == "null" || len(data) == 0 {
+		return nil
+	}
+
+	switch data[0] {
+	case '"':
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		parsed, err := Parse(s)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	case '{':
+		var obj struct {
+			Amount   json.RawMessage `json:"amount"`
+			Currency string          `json:"currency"`
+		}
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return err
+		}
+		amount := strings.Trim(string(obj.Amount), `"`)
+		parsed, err := Parse(strings.TrimSpace(amount + " " + obj.Currency))
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("money: invalid amount %s", data)
+	}
+	minor, err := parseAmount(n.String(), Digits(""))
+	if err != nil {
+		return err
+	}
+	*m = Money{Amount: minor}
+	return nil
+}
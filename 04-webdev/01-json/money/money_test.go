@@ -0,0 +1,128 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Money
+	}{
+		{"34.3", Money{3430, ""}},
+		{"17.8", Money{1780, ""}},
+		{"34.30 USD", Money{3430, "USD"}},
+		{"usd 34.30", Money{3430, "USD"}},
+		{"-0.5", Money{-50, ""}},
+		{"0.125", Money{12, ""}},
+		{"0.135", Money{14, ""}},
+		{"-0.125", Money{-12, ""}},
+		{"1500.5 JPY", Money{1500, "JPY"}},
+		{"1.2345 KWD", Money{1234, "KWD"}},
+	}
+	for _, test := range tests {
+		got, err := Parse(test.in)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", test.in, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("Parse(%q) was incorrect, Actual: %+v, Expected: %+v", test.in, got, test.want)
+		}
+	}
+
+	for _, in := range []string{"", "abc", "1.2 US", "1 2 3", "99999999999999999999", "1/3", "1e2", "1e1000000", "+1", ".5", "1.", "0x10"} {
+		if _, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q) should fail", in)
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	a := MustParse("10.00 USD")
+	b := MustParse("0.05 USD")
+
+	sum, err := a.Add(b)
+	if err != nil || sum != MustParse("10.05 USD") {
+		t.Errorf("Add was incorrect, Actual: %v %v, Expected: 10.05 USD", sum, err)
+	}
+	diff, err := b.Sub(a)
+	if err != nil || diff.String() != "-9.95 USD" {
+		t.Errorf("Sub was incorrect, Actual: %v %v, Expected: -9.95 USD", diff, err)
+	}
+	total, err := b.Mul(3)
+	if err != nil || total.Amount != 15 {
+		t.Errorf("Mul was incorrect, Actual: %v %v, Expected: 0.15 USD", total, err)
+	}
+	// 0.05 / 2 = 0.025, rounds to the even 0.02
+	half, err := b.Div(2)
+	if err != nil || half.Amount != 2 {
+		t.Errorf("Div was incorrect, Actual: %v %v, Expected: 0.02 USD", half, err)
+	}
+	// 0.15 / 2 = 0.075, rounds to the even 0.08
+	half, _ = total.Div(2)
+	if half.Amount != 8 {
+		t.Errorf("Div was incorrect, Actual: %v, Expected: 0.08 USD", half)
+	}
+	if _, err := a.Div(0); !errors.Is(err, ErrDivideByZero) {
+		t.Errorf("Div(0) should fail with ErrDivideByZero, Actual: %v", err)
+	}
+	if _, err := a.Add(MustParse("1 EUR")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Add should fail with ErrCurrencyMismatch, Actual: %v", err)
+	}
+	if _, err := Sum(a, b, MustParse("1")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Sum should fail with ErrCurrencyMismatch, Actual: %v", err)
+	}
+	if _, err := New(1<<62, "").Mul(4); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Mul should fail with ErrOverflow, Actual: %v", err)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Money
+		out  string
+	}{
+		{`34.3`, Money{3430, ""}, `34.30`},
+		{`"34.3"`, Money{3430, ""}, `34.30`},
+		{`"34.30 USD"`, Money{3430, "USD"}, `"34.30 USD"`},
+		{`{"amount": 34.3, "currency": "EUR"}`, Money{3430, "EUR"}, `"34.30 EUR"`},
+		{`{"amount": "1200", "currency": "JPY"}`, Money{1200, "JPY"}, `"1200 JPY"`},
+	}
+	for _, test := range tests {
+		var m Money
+		if err := json.Unmarshal([]byte(test.in), &m); err != nil {
+			t.Errorf("Unmarshal(%s): %v", test.in, err)
+			continue
+		}
+		if m != test.want {
+			t.Errorf("Unmarshal(%s) was incorrect, Actual: %+v, Expected: %+v", test.in, m, test.want)
+		}
+		out, err := json.Marshal(m)
+		if err != nil || string(out) != test.out {
+			t.Errorf("Marshal(%+v) was incorrect, Actual: %s %v, Expected: %s", m, out, err, test.out)
+		}
+	}
+}
+
+func TestText(t *testing.T) {
+	var m Money
+	if err := m.UnmarshalText([]byte(" 15.5 usd ")); err != nil || m != (Money{1550, "USD"}) {
+		t.Errorf("UnmarshalText was incorrect, Actual: %+v %v, Expected: 15.50 USD", m, err)
+	}
+	out, _ := m.MarshalText()
+	if string(out) != "15.50 USD" {
+		t.Errorf("MarshalText was incorrect, Actual: %s, Expected: 15.50 USD", out)
+	}
+	if err := m.UnmarshalText([]byte("abc")); err == nil {
+		t.Error("UnmarshalText(abc) should fail")
+	}
+	// JSON still uses MarshalJSON, a plain number without currency
+	out, _ = json.Marshal(New(1500, ""))
+	if string(out) != "15.00" {
+		t.Errorf("Marshal was incorrect, Actual: %s, Expected: 15.00", out)
+	}
+}
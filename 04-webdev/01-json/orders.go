@@ -1,38 +1,37 @@
+//go:build ignore
+
+// Run with: go run orders.go
 package main
 
 import (
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
-)
-
-type OrderItem struct {
-	Id       int     `json: "id"`
-	Quantity int     `json: "quantity"`
-	Total    float32 `json: "total"`
-}
-
-type Order struct {
-	Id    int         `json: "id"`
-	Items []OrderItem `json: items`
-}
+	"io"
+	"log"
+	"os"
 
-type Response struct {
-	Orders []Order `json: orders`
-}
+	"json-example/stream"
+)
 
 func main() {
-	file, _ := ioutil.ReadFile("orders.json")
-
-	data := Response{}
+	file, err := os.Open("orders.json")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
 
-	_ = json.Unmarshal([]byte(file), &data)
-	fmt.Println(data)
-	for i := 0; i < len(data.Orders); i++ {
-		fmt.Println("Order Id: ", data.Orders[i].Id)
+	orders := stream.NewReader(file)
+	for {
+		order, err := orders.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println("Order Id: ", order.Id)
 
-		for j := 0; j < len(data.Orders[i].Items); j++ {
-			item := data.Orders[i].Items[j]
+		for j := 0; j < len(order.Items); j++ {
+			item := order.Items[j]
 			fmt.Println("Item id", item.Id)
 			fmt.Println("Item quantity", item.Quantity)
 			fmt.Println("Item total", item.Total)
@@ -0,0 +1,90 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Formats lists the names accepted by Write.
+var Formats = []string{"table", "csv", "json"}
+
+// Write renders the summary as a table, CSV or JSON.
+func Write(w io.Writer, s Summary, format string) error {
+	switch format {
+	case "table", "":
+		return WriteTable(w, s)
+	case "csv":
+		return WriteCSV(w, s)
+	case "json":
+		return WriteJSON(w, s)
+	}
+	return fmt.Errorf("unknown format %q, use one of %s", format, strings.Join(Formats, ", "))
+}
+
+func WriteJSON(w io.Writer, s Summary) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// WriteTable writes one aligned table per section, for reading in a terminal.
+func WriteTable(w io.Writer, s Summary) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', tabwriter.AlignRight)
+
+	fmt.Fprintln(tw, "ORDER\tITEMS\tQUANTITY\tTOTAL\t")
+	for _, o := range s.Orders {
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%s\t\n", o.Id, o.Items, o.Quantity, o.Total)
+	}
+	fmt.Fprintf(tw, "all\t\t\t%s\t\n", s.Total)
+	fmt.Fprintln(tw)
+
+	fmt.Fprintln(tw, "TOP ITEM\tQUANTITY\tREVENUE\t")
+	for _, item := range s.TopItems {
+		fmt.Fprintf(tw, "%d\t%d\t%s\t\n", item.Id, item.Quantity, item.Revenue)
+	}
+	fmt.Fprintln(tw)
+
+	fmt.Fprintln(tw, "QUANTITY\tITEMS\t\t")
+	for _, b := range s.Histogram {
+		fmt.Fprintf(tw, "%d\t%d\t%s\t\n", b.Quantity, b.Items, strings.Repeat("#", b.Items))
+	}
+	fmt.Fprintln(tw)
+
+	a := s.Averages
+	fmt.Fprintln(tw, "AVERAGE\t\t")
+	fmt.Fprintf(tw, "order total\t%s\t\n", a.OrderTotal)
+	fmt.Fprintf(tw, "items per order\t%.2f\t\n", a.ItemsPerOrder)
+	fmt.Fprintf(tw, "quantity per item\t%.2f\t\n", a.QuantityPerItem)
+	fmt.Fprintf(tw, "unit price\t%s\t\n", a.UnitPrice)
+	return tw.Flush()
+}
+
+// WriteCSV writes every section into one sheet, the first column names the section.
+func WriteCSV(w io.Writer, s Summary) error {
+	cw := csv.NewWriter(w)
+	itoa := strconv.Itoa
+	ftoa := func(f float64) string { return strconv.FormatFloat(f, 'f', 2, 64) }
+
+	cw.Write([]string{"section", "id", "items", "quantity", "amount", "currency"})
+	for _, o := range s.Orders {
+		cw.Write([]string{"order", itoa(o.Id), itoa(o.Items), itoa(o.Quantity), o.Total.Decimal(), o.Total.Currency})
+	}
+	cw.Write([]string{"total", "", "", "", s.Total.Decimal(), s.Total.Currency})
+	for _, item := range s.TopItems {
+		cw.Write([]string{"item", itoa(item.Id), "", itoa(item.Quantity), item.Revenue.Decimal(), item.Revenue.Currency})
+	}
+	for _, b := range s.Histogram {
+		cw.Write([]string{"histogram", "", itoa(b.Items), itoa(b.Quantity), "", ""})
+	}
+	a := s.Averages
+	cw.Write([]string{"average", "", ftoa(a.ItemsPerOrder), ftoa(a.QuantityPerItem), a.OrderTotal.Decimal(), a.OrderTotal.Currency})
+	cw.Write([]string{"average_unit_price", "", "", "", a.UnitPrice.Decimal(), a.UnitPrice.Currency})
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,247 @@
+// Package report summarizes orders: totals per order, quantity histograms,
+// the items bringing in the most revenue and averages.
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"json-example/model"
+	"json-example/money"
+)
+
+// NoMax is the Max of a range without an upper bound, like "10-".
+const NoMax = -1
+
+// Range is an inclusive range of order ids.
+type Range struct {
+	Min int
+	Max int // NoMax for no upper bound
+}
+
+func (r Range) Contains(id int) bool {
+	return id >= r.Min && (r.Max == NoMax || id <= r.Max)
+}
+
+// Filter keeps orders whose id is in one of its ranges, an empty Filter keeps all orders.
+type Filter []Range
+
+// ParseFilter reads ranges like "1-10,15,20-".
+func ParseFilter(s string) (Filter, error) {
+	var filter Filter
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		var r Range
+		var err error
+		if i := strings.Index(part, "-"); i >= 0 {
+			r.Min, err = strconv.Atoi(part[:i])
+			r.Max = NoMax
+			if err == nil && part[i+1:] != "" {
+				r.Max, err = strconv.Atoi(part[i+1:])
+				if err == nil && r.Max < r.Min {
+					err = fmt.Errorf("%d is less than %d", r.Max, r.Min)
+				}
+			}
+		} else {
+			r.Min, err = strconv.Atoi(part)
+			r.Max = r.Min
+		}
+		if err != nil || r.Min < 0 {
+			return nil, fmt.Errorf("invalid id range %q", part)
+		}
+		filter = append(filter, r)
+	}
+	return filter, nil
+}
+
+func (f Filter) Match(id int) bool {
+	if len(f) == 0 {
+		return true
+	}
+	for _, r := range f {
+		if r.Contains(id) {
+			return true
+		}
+	}
+	return false
+}
+
+type OrderTotal struct {
+	Id       int         `json:"id"`
+	Items    int         `json:"items"`
+	Quantity int         `json:"quantity"`
+	Total    money.Money `json:"total"`
+}
+
+type ItemRevenue struct {
+	Id       int         `json:"id"`
+	Quantity int         `json:"quantity"`
+	Revenue  money.Money `json:"revenue"`
+}
+
+// Bucket counts the order items bought in the same quantity.
+type Bucket struct {
+	Quantity int `json:"quantity"`
+	Items    int `json:"items"`
+}
+
+type Averages struct {
+	OrderTotal      money.Money `json:"order_total"`
+	ItemsPerOrder   float64     `json:"items_per_order"`
+	QuantityPerItem float64     `json:"quantity_per_item"`
+	UnitPrice       money.Money `json:"unit_price"`
+}
+
+type Summary struct {
+	Orders    []OrderTotal  `json:"orders"`
+	Total     money.Money   `json:"total"`
+	Histogram []Bucket      `json:"histogram"`
+	TopItems  []ItemRevenue `json:"top_items"`
+	Averages  Averages      `json:"averages"`
+}
+
+// Builder collects orders one at a time, so it works together with the stream package.
+type Builder struct {
+	Filter Filter
+	Top    int // how many items to keep in TopItems, 0 keeps all
+
+	orders    []OrderTotal
+	total     money.Money
+	quantity  int
+	lines     int
+	histogram map[int]int
+	items     map[int]*ItemRevenue
+}
+
+func NewBuilder(filter Filter, top int) *Builder {
+	return &Builder{
+		Filter:    filter,
+		Top:       top,
+		histogram: make(map[int]int),
+		items:     make(map[int]*ItemRevenue),
+	}
+}
+
+// Add counts order unless it's filtered out. All amounts must share one currency.
+func (b *Builder) Add(order model.Order) error {
+	if !b.Filter.Match(order.Id) {
+		return nil
+	}
+
+	// work out the new totals first, so an order that fails leaves the report as it was
+	line := OrderTotal{Id: order.Id, Items: len(order.Items), Total: money.New(0, b.total.Currency)}
+	revenues := make(map[int]money.Money)
+	for i, item := range order.Items {
+		var err error
+		if i == 0 && b.lines == 0 {
+			// the first item decides the currency of the report
+			line.Total = item.Total
+		} else {
+			line.Total, err = line.Total.Add(item.Total)
+			if err != nil {
+				return fmt.Errorf("order %d: %w", order.Id, err)
+			}
+		}
+		line.Quantity += item.Quantity
+
+		revenue, ok := revenues[item.Id]
+		if !ok {
+			revenue = money.New(0, item.Total.Currency)
+			if known, ok := b.items[item.Id]; ok {
+				revenue = known.Revenue
+			}
+		}
+		revenues[item.Id], err = revenue.Add(item.Total)
+		if err != nil {
+			return fmt.Errorf("order %d, item %d: %w", order.Id, item.Id, err)
+		}
+	}
+
+	total := line.Total
+	if b.lines > 0 {
+		var err error
+		total, err = b.total.Add(line.Total)
+		if err != nil {
+			return fmt.Errorf("order %d: %w", order.Id, err)
+		}
+	}
+
+	for _, item := range order.Items {
+		b.histogram[item.Quantity]++
+		revenue, ok := b.items[item.Id]
+		if !ok {
+			revenue = &ItemRevenue{Id: item.Id}
+			b.items[item.Id] = revenue
+		}
+		revenue.Quantity += item.Quantity
+		revenue.Revenue = revenues[item.Id]
+	}
+	b.total = total
+	b.quantity += line.Quantity
+	b.lines += len(order.Items)
+	b.orders = append(b.orders, line)
+	return nil
+}
+
+func (b *Builder) Summary() Summary {
+	s := Summary{
+		Orders:    b.orders,
+		Total:     b.total,
+		Histogram: make([]Bucket, 0, len(b.histogram)),
+		TopItems:  make([]ItemRevenue, 0, len(b.items)),
+	}
+	if s.Orders == nil {
+		s.Orders = []OrderTotal{}
+	}
+
+	for quantity, count := range b.histogram {
+		s.Histogram = append(s.Histogram, Bucket{Quantity: quantity, Items: count})
+	}
+	sort.Slice(s.Histogram, func(i, j int) bool {
+		return s.Histogram[i].Quantity < s.Histogram[j].Quantity
+	})
+
+	for _, item := range b.items {
+		s.TopItems = append(s.TopItems, *item)
+	}
+	sort.Slice(s.TopItems, func(i, j int) bool {
+		a, b := s.TopItems[i], s.TopItems[j]
+		if a.Revenue.Amount != b.Revenue.Amount {
+			return a.Revenue.Amount > b.Revenue.Amount
+		}
+		return a.Id < b.Id
+	})
+	if b.Top > 0 && len(s.TopItems) > b.Top {
+		s.TopItems = s.TopItems[:b.Top]
+	}
+
+	s.Averages.OrderTotal = money.New(0, b.total.Currency)
+	s.Averages.UnitPrice = money.New(0, b.total.Currency)
+	if n := len(b.orders); n > 0 {
+		s.Averages.OrderTotal, _ = b.total.Div(int64(n))
+		s.Averages.ItemsPerOrder = float64(b.lines) / float64(n)
+	}
+	if b.lines > 0 {
+		s.Averages.QuantityPerItem = float64(b.quantity) / float64(b.lines)
+	}
+	if b.quantity > 0 {
+		s.Averages.UnitPrice, _ = b.total.Div(int64(b.quantity))
+	}
+	return s
+}
+
+// Build summarizes a slice of orders.
+func Build(orders []model.Order, filter Filter, top int) (Summary, error) {
+	b := NewBuilder(filter, top)
+	for _, order := range orders {
+		if err := b.Add(order); err != nil {
+			return Summary{}, err
+		}
+	}
+	return b.Summary(), nil
+}
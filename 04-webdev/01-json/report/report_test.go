@@ -0,0 +1,129 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"json-example/model"
+	"json-example/money"
+)
+
+var orders = []model.Order{
+	{Id: 1, Items: []model.OrderItem{
+		{Id: 1, Quantity: 3, Total: money.New(3430, "")},
+		{Id: 2, Quantity: 2, Total: money.New(1780, "")},
+	}},
+	{Id: 2, Items: []model.OrderItem{
+		{Id: 3, Quantity: 3, Total: money.New(1000, "")},
+		{Id: 1, Quantity: 2, Total: money.New(10050, "")},
+	}},
+	{Id: 7},
+}
+
+func TestParseFilter(t *testing.T) {
+	filter, err := ParseFilter("1-3, 7,10-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Filter{{1, 3}, {7, 7}, {10, NoMax}}
+	if !reflect.DeepEqual(filter, expected) {
+		t.Errorf("Filter was incorrect, Actual: %v, Expected: %v", filter, expected)
+	}
+	for id, want := range map[int]bool{0: false, 2: true, 5: false, 7: true, 100: true} {
+		if filter.Match(id) != want {
+			t.Errorf("Match(%d) was incorrect, Expected: %v", id, want)
+		}
+	}
+	zero, err := ParseFilter("0")
+	if err != nil || !zero.Match(0) || zero.Match(1) {
+		t.Errorf("ParseFilter(0) was incorrect, Actual: %v %v, Expected: only id 0", zero, err)
+	}
+	for _, bad := range []string{"a", "3-1", "5-0", "-2", "1-b"} {
+		if _, err := ParseFilter(bad); err == nil {
+			t.Errorf("ParseFilter(%q) should fail", bad)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	s, err := Build(orders, nil, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Total.Amount != 16260 {
+		t.Errorf("Total was incorrect, Actual: %v, Expected: 162.60", s.Total)
+	}
+	if len(s.Orders) != 3 || s.Orders[1].Total.Amount != 11050 || s.Orders[1].Quantity != 5 {
+		t.Errorf("Orders were incorrect, Actual: %+v", s.Orders)
+	}
+	expectedTop := []ItemRevenue{{Id: 1, Quantity: 5, Revenue: money.New(13480, "")}, {Id: 2, Quantity: 2, Revenue: money.New(1780, "")}}
+	if !reflect.DeepEqual(s.TopItems, expectedTop) {
+		t.Errorf("TopItems was incorrect, Actual: %+v, Expected: %+v", s.TopItems, expectedTop)
+	}
+	expectedHistogram := []Bucket{{Quantity: 2, Items: 2}, {Quantity: 3, Items: 2}}
+	if !reflect.DeepEqual(s.Histogram, expectedHistogram) {
+		t.Errorf("Histogram was incorrect, Actual: %+v, Expected: %+v", s.Histogram, expectedHistogram)
+	}
+	// 162.60 / 3 orders rounds to 54.20
+	if s.Averages.OrderTotal.Amount != 5420 || s.Averages.ItemsPerOrder != 4.0/3 {
+		t.Errorf("Averages were incorrect, Actual: %+v", s.Averages)
+	}
+
+	s, err = Build(orders, Filter{{2, 2}}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Orders) != 1 || s.Total.Amount != 11050 {
+		t.Errorf("Filtered summary was incorrect, Actual: %+v", s)
+	}
+}
+
+func TestBuildCurrencyMismatch(t *testing.T) {
+	mixed := []model.Order{
+		{Id: 1, Items: []model.OrderItem{{Id: 1, Quantity: 1, Total: money.New(100, "USD")}}},
+		{Id: 2, Items: []model.OrderItem{{Id: 2, Quantity: 1, Total: money.New(100, "EUR")}}},
+	}
+	_, err := Build(mixed, nil, 0)
+	if !errors.Is(err, money.ErrCurrencyMismatch) {
+		t.Errorf("Build should fail with ErrCurrencyMismatch, Actual: %v", err)
+	}
+}
+
+func TestAddMismatchChangesNothing(t *testing.T) {
+	usd := model.Order{Id: 1, Items: []model.OrderItem{{Id: 1, Quantity: 2, Total: money.New(200, "USD")}}}
+	mixed := model.Order{Id: 2, Items: []model.OrderItem{
+		{Id: 1, Quantity: 3, Total: money.New(300, "USD")},
+		{Id: 2, Quantity: 1, Total: money.New(100, "EUR")},
+	}}
+
+	b := NewBuilder(nil, 0)
+	if err := b.Add(usd); err != nil {
+		t.Fatal(err)
+	}
+	before := b.Summary()
+	if err := b.Add(mixed); !errors.Is(err, money.ErrCurrencyMismatch) {
+		t.Errorf("Add should fail with ErrCurrencyMismatch, Actual: %v", err)
+	}
+	if after := b.Summary(); !reflect.DeepEqual(after, before) {
+		t.Errorf("Add of a failing order was incorrect, Actual: %+v, Expected: %+v", after, before)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	s, _ := Build(orders, nil, 0)
+	for _, format := range Formats {
+		var buf bytes.Buffer
+		if err := Write(&buf, s, format); err != nil {
+			t.Errorf("%s: %v", format, err)
+		}
+		if !strings.Contains(buf.String(), "162.60") {
+			t.Errorf("%s output is missing the total:\n%s", format, buf.String())
+		}
+	}
+	if err := Write(&bytes.Buffer{}, s, "xml"); err == nil {
+		t.Error("Write should fail for an unknown format")
+	}
+}
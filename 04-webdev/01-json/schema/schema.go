@@ -0,0 +1,188 @@
+// Package schema generates JSON Schema documents from Go structs and
+// validates JSON data against them.
+//
+// The generated schemas don't allow properties the struct doesn't have,
+// so a typo like "nmae" in a data file is reported instead of ignored.
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"json-example/money"
+)
+
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is the subset of JSON Schema that Generate produces and Validate understands.
+type Schema struct {
+	Schema      string             `json:"$schema,omitempty"`
+	Title       string             `json:"title,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Pattern     string             `json:"pattern,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	OneOf       []*Schema          `json:"oneOf,omitempty"`
+
+	// AdditionalProperties is either a bool or a *Schema for map values.
+	AdditionalProperties interface{} `json:"additionalProperties,omitempty"`
+}
+
+// Custom holds hand-written schemas for types that marshal themselves.
+var Custom = map[reflect.Type]*Schema{
+	reflect.TypeOf(time.Time{}): {Type: "string", Format: "date-time"},
+	reflect.TypeOf(money.Money{}): {
+		Description: `an amount like 34.30, "34.30 USD" or {"amount": 34.3, "currency": "USD"}`,
+		OneOf: []*Schema{
+			{Type: "number"},
+			{Type: "string", Pattern: `^(?:[A-Za-z]{3} )?-?[0-9]+(?:\.[0-9]+)?(?: [A-Za-z]{3})?$`},
+			{
+				Type: "object",
+				Properties: map[string]*Schema{
+					"amount":   {OneOf: []*Schema{{Type: "number"}, {Type: "string"}}},
+					"currency": {Type: "string", Pattern: `^[A-Za-z]{3}$`},
+				},
+				Required:             []string{"amount"},
+				AdditionalProperties: false,
+			},
+		},
+	},
+}
+
+// Generate builds the schema of v's type, v is usually a zero struct like model.Person{}.
+func Generate(v interface{}) (*Schema, error) {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return nil, fmt.Errorf("schema: can't generate a schema for nil")
+	}
+	s, err := generate(t, map[reflect.Type]bool{})
+	if err != nil {
+		return nil, err
+	}
+	s.Schema = Draft
+	if s.Title == "" {
+		s.Title = t.Name()
+	}
+	return s, nil
+}
+
+func generate(t reflect.Type, visiting map[reflect.Type]bool) (*Schema, error) {
+	if custom, ok := Custom[t]; ok {
+		s := *custom
+		return &s, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}, nil
+	case reflect.String:
+		return &Schema{Type: "string"}, nil
+	case reflect.Ptr:
+		elem, err := generate(t.Elem(), visiting)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{OneOf: []*Schema{{Type: "null"}, elem}}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// encoding/json writes []byte as base64
+			return &Schema{Type: "string", Format: "byte"}, nil
+		}
+		items, err := generate(t.Elem(), visiting)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("schema: map keys of %v must be strings", t)
+		}
+		values, err := generate(t.Elem(), visiting)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Interface:
+		return &Schema{}, nil
+	case reflect.Struct:
+		if visiting[t] {
+			return nil, fmt.Errorf("schema: recursive type %v is not supported", t)
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		s := &Schema{Type: "object", Properties: map[string]*Schema{}, AdditionalProperties: false}
+		if err := addFields(s, t, visiting); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+	return nil, fmt.Errorf("schema: unsupported type %v", t)
+}
+
+// addFields adds the properties of struct t to s, following the encoding/json rules.
+func addFields(s *Schema, t reflect.Type, visiting map[reflect.Type]bool) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts := tag, ""
+		if i := strings.Index(tag, ","); i >= 0 {
+			name, opts = tag[:i], tag[i+1:]
+		}
+
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				// embedded struct, its fields are promoted
+				if err := addFields(s, ft, visiting); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop, err := generate(field.Type, visiting)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+		}
+		if hasOption(opts, "string") && prop.Type != "" {
+			prop = &Schema{Type: "string"}
+		}
+		s.Properties[name] = prop
+		if !hasOption(opts, "omitempty") && !hasOption(opts, "omitzero") {
+			s.Required = append(s.Required, name)
+		}
+	}
+	return nil
+}
+
+func hasOption(opts string, option string) bool {
+	for _, o := range strings.Split(opts, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package schema
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"json-example/model"
+)
+
+func TestGenerate(t *testing.T) {
+	s, err := Generate(model.Person{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Type != "object" || s.Properties["name"].Type != "string" || s.Properties["age"].Type != "integer" {
+		t.Errorf("Person schema was incorrect, Actual: %+v", s)
+	}
+	if s.AdditionalProperties != false {
+		t.Errorf("additionalProperties should be false, Actual: %v", s.AdditionalProperties)
+	}
+
+	type inner struct {
+		Note string `json:"note"`
+	}
+	type sample struct {
+		inner
+		Id      int               `json:"id"`
+		Tags    []string          `json:"tags,omitempty"`
+		Labels  map[string]string `json:"labels,omitempty"`
+		Count   int               `json:"count,string"`
+		Skipped string            `json:"-"`
+		hidden  string
+	}
+	s, err = Generate(sample{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	if len(names) != 5 || s.Properties["note"] == nil || s.Properties["count"].Type != "string" {
+		t.Errorf("Properties were incorrect, Actual: %v", names)
+	}
+	if !reflect.DeepEqual(s.Required, []string{"note", "id", "count"}) {
+		t.Errorf("Required was incorrect, Actual: %v", s.Required)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	s, err := Generate(model.Response{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile("../orders.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	violations, err := Validate(s, data)
+	if err != nil || len(violations) != 0 {
+		t.Errorf("orders.json should be valid, Actual: %v %v", violations, err)
+	}
+
+	bad := `{"orders": [{"id": 1, "items": [{"id": "x", "totl": 3}, {"total": "3.50 USD"}, {"total": {"amount": "1", "currency": "EUR"}}]}], "a/b": 1}`
+	violations, err = Validate(s, []byte(bad))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"/orders/0/items/0", "/orders/0/items/0/id", "/orders/0/items/0/totl", "/a~1b"}
+	var pointers []string
+	for _, v := range violations {
+		pointers = append(pointers, v.Pointer)
+	}
+	if len(pointers) != len(expected) {
+		t.Fatalf("Violations were incorrect, Actual: %v, Expected pointers: %v", violations, expected)
+	}
+	for _, p := range expected {
+		found := false
+		for _, v := range pointers {
+			found = found || v == p
+		}
+		if !found {
+			t.Errorf("Missing violation at %s, Actual: %v", p, violations)
+		}
+	}
+
+	if _, err := Validate(s, []byte(`{"orders": [}`)); err == nil {
+		t.Error("Validate should fail on invalid JSON")
+	}
+}
@@ -0,0 +1,229 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"strings-example/textutil"
+)
+
+// Violation is a place where the data doesn't match the schema.
+// Pointer is a JSON Pointer (RFC 6901) to the offending value, "" is the whole document.
+type Violation struct {
+	Pointer string `json:"pointer"`
+	Message string `json:"message"`
+}
+
+func (v Violation) String() string {
+	pointer := v.Pointer
+	if pointer == "" {
+		pointer = "/"
+	}
+	return pointer + ": " + v.Message
+}
+
+// Validate checks a JSON document against s. The error is only set when
+// data isn't valid JSON, schema violations are returned as a list.
+func Validate(s *Schema, data []byte) ([]Violation, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("schema: unexpected data after the JSON document")
+	}
+
+	v := &validator{patterns: map[string]*regexp.Regexp{}}
+	v.validate(s, doc, "")
+	return v.violations, nil
+}
+
+type validator struct {
+	violations []Violation
+	patterns   map[string]*regexp.Regexp
+}
+
+func (v *validator) report(pointer string, format string, args ...interface{}) {
+	v.violations = append(v.violations, Violation{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+}
+
+// escape encodes a property name as a JSON Pointer reference token.
+func escape(name string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
+
+func (v *validator) validate(s *Schema, value interface{}, pointer string) {
+	if len(s.OneOf) > 0 {
+		v.validateOneOf(s, value, pointer)
+		return
+	}
+	if s.Type != "" && !hasType(value, s.Type) {
+		v.report(pointer, "expected %s, found %s", s.Type, typeOf(value))
+		return
+	}
+
+	switch value := value.(type) {
+	case string:
+		if s.Pattern != "" {
+			re, err := v.pattern(s.Pattern)
+			if err != nil {
+				v.report(pointer, "schema pattern %q is invalid: %v", s.Pattern, err)
+			} else if !re.MatchString(value) {
+				v.report(pointer, "%q does not match %s", value, s.Pattern)
+			}
+		}
+	case []interface{}:
+		if s.Items != nil {
+			for i, item := range value {
+				v.validate(s.Items, item, pointer+"/"+strconv.Itoa(i))
+			}
+		}
+	case map[string]interface{}:
+		v.validateObject(s, value, pointer)
+	}
+}
+
+func (v *validator) validateObject(s *Schema, value map[string]interface{}, pointer string) {
+	for _, name := range s.Required {
+		if _, ok := value[name]; !ok {
+			v.report(pointer, "missing required property %q", name)
+		}
+	}
+
+	names := make([]string, 0, len(value))
+	for name := range value {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		child := pointer + "/" + escape(name)
+		if prop, ok := s.Properties[name]; ok {
+			v.validate(prop, value[name], child)
+			continue
+		}
+		switch extra := s.AdditionalProperties.(type) {
+		case bool:
+			if !extra {
+				v.report(child, "unknown property %q%s", name, suggest(name, s.Properties))
+			}
+		case *Schema:
+			v.validate(extra, value[name], child)
+		}
+	}
+}
+
+func (v *validator) validateOneOf(s *Schema, value interface{}, pointer string) {
+	matches := 0
+	for _, option := range s.OneOf {
+		sub := &validator{patterns: v.patterns}
+		sub.validate(option, value, pointer)
+		if len(sub.violations) == 0 {
+			matches++
+		}
+	}
+	if matches == 1 {
+		return
+	}
+
+	var types []string
+	for _, option := range s.OneOf {
+		if option.Type != "" {
+			types = append(types, option.Type)
+		}
+	}
+	if matches == 0 {
+		if s.Description != "" {
+			v.report(pointer, "expected %s, found %s", s.Description, describe(value))
+		} else {
+			v.report(pointer, "expected %s, found %s", strings.Join(types, " or "), describe(value))
+		}
+		return
+	}
+	v.report(pointer, "matches %d of the allowed schemas, expected exactly one", matches)
+}
+
+func (v *validator) pattern(p string) (*regexp.Regexp, error) {
+	if re, ok := v.patterns[p]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(p)
+	if err != nil {
+		return nil, err
+	}
+	v.patterns[p] = re
+	return re, nil
+}
+
+func hasType(value interface{}, t string) bool {
+	switch t {
+	case "integer":
+		n, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		_, err := n.Int64()
+		return err == nil
+	case "number":
+		_, ok := value.(json.Number)
+		return ok
+	}
+	return typeOf(value) == t
+}
+
+func typeOf(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if _, err := value.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// describe shows short values next to their type.
+func describe(value interface{}) string {
+	switch value := value.(type) {
+	case string:
+		return fmt.Sprintf("string %q", value)
+	case json.Number:
+		return typeOf(value) + " " + value.String()
+	case bool:
+		return fmt.Sprintf("boolean %v", value)
+	}
+	return typeOf(value)
+}
+
+// suggest points to a known property that's spelled almost the same.
+func suggest(name string, properties map[string]*Schema) string {
+	best, bestDistance := "", 3
+	for known := range properties {
+		d := textutil.Levenshtein(strings.ToLower(name), strings.ToLower(known))
+		if d < bestDistance || d == bestDistance && known < best {
+			best, bestDistance = known, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(", did you mean %q?", best)
+}
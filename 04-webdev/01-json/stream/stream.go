@@ -0,0 +1,410 @@
+// Package stream reads and writes orders one at a time, so exports of
+// hundreds of megabytes never have to fit in memory.
+//
+// Two layouts are supported. The document layout used by orders.json:
+//
+//	{ "orders": [ {...}, {...} ] }
+//
+// and JSON Lines, where every line holds one order:
+//
+//	{"id":1,"items":[...]}
+//	{"id":2,"items":[...]}
+package stream
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"json-example/model"
+)
+
+// Error tells where in the input a problem was found.
+// Line and Column start at 1, Offset is the byte offset from the start of the input.
+type Error struct {
+	Line   int
+	Column int
+	Offset int64
+	Err    error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("line %d, column %d (offset %d): %v", e.Line, e.Column, e.Offset, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// OrderReader is implemented by Reader and LinesReader.
+// Next returns io.EOF when there are no more orders.
+type OrderReader interface {
+	Next() (model.Order, error)
+}
+
+// OrderWriter is implemented by Writer and LinesWriter.
+// Close must be called to finish the output, it doesn't close the underlying writer.
+type OrderWriter interface {
+	Write(order model.Order) error
+	Close() error
+}
+
+// Reader yields the orders of a document shaped like orders.json.
+// A top-level array of orders is accepted as well.
+type Reader struct {
+	dec     *json.Decoder
+	pos     *position
+	inArray bool
+	done    bool
+	err     error
+}
+
+func NewReader(r io.Reader) *Reader {
+	pos := &position{r: r, lastForgotten: -1}
+	return &Reader{dec: json.NewDecoder(pos), pos: pos}
+}
+
+// Next returns the next order, or io.EOF after the last one.
+func (r *Reader) Next() (model.Order, error) {
+	if r.err != nil {
+		return model.Order{}, r.err
+	}
+	if r.done {
+		return model.Order{}, io.EOF
+	}
+	if !r.inArray {
+		if err := r.seek(); err != nil {
+			return model.Order{}, r.fail(err)
+		}
+		if r.done {
+			return model.Order{}, io.EOF
+		}
+	}
+
+	if !r.dec.More() {
+		if err := r.finish(); err != nil {
+			return model.Order{}, r.fail(err)
+		}
+		return model.Order{}, io.EOF
+	}
+
+	start := r.valueStart()
+	var order model.Order
+	err := r.dec.Decode(&order)
+	if err != nil {
+		var typeErr *json.UnmarshalTypeError
+		var syntaxErr *json.SyntaxError
+		switch {
+		case errors.As(err, &typeErr):
+			return model.Order{}, r.fail(r.pos.errorAt(start+typeErr.Offset-1, err))
+		case errors.As(err, &syntaxErr), err == io.EOF, err == io.ErrUnexpectedEOF:
+			return model.Order{}, r.fail(err)
+		}
+		// like an invalid amount, found by an UnmarshalJSON method, point at the order
+		return model.Order{}, r.fail(r.pos.errorAt(start, err))
+	}
+	r.pos.forget(r.dec.InputOffset())
+	return order, nil
+}
+
+func (r *Reader) fail(err error) error {
+	var posErr *Error
+	if !errors.As(err, &posErr) {
+		err = r.wrap(err)
+	}
+	r.err = err
+	return err
+}
+
+// wrap attaches the position of err to it.
+func (r *Reader) wrap(err error) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		if syntaxErr.Offset >= r.pos.read {
+			// ran out of input, point just past the end
+			return r.pos.errorAt(r.pos.read, err)
+		}
+		return r.pos.errorAt(syntaxErr.Offset-1, err)
+	}
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return r.pos.errorAt(r.pos.read, err)
+}
+
+// valueStart finds where the next value begins by skipping the whitespace
+// and comma the decoder has buffered but not consumed yet. It reads only
+// those bytes, not the whole buffer.
+func (r *Reader) valueStart() int64 {
+	offset := r.dec.InputOffset()
+	buffered := r.dec.Buffered()
+	var c [1]byte
+	for {
+		if n, _ := buffered.Read(c[:]); n == 0 {
+			break
+		}
+		if c[0] != ' ' && c[0] != '\t' && c[0] != '\r' && c[0] != '\n' && c[0] != ',' {
+			break
+		}
+		offset++
+	}
+	return offset
+}
+
+// seek moves the decoder to the first element of the orders array.
+func (r *Reader) seek() error {
+	tok, err := r.dec.Token()
+	if err == io.EOF {
+		return r.pos.errorAt(r.pos.read, errors.New("empty input"))
+	}
+	if err != nil {
+		return err
+	}
+
+	switch tok {
+	case json.Delim('['):
+		r.inArray = true
+		return nil
+	case json.Delim('{'):
+	default:
+		return r.pos.errorAt(r.dec.InputOffset()-1, fmt.Errorf("expected an object or an array, found %v", tok))
+	}
+
+	for r.dec.More() {
+		key, err := r.dec.Token()
+		if err != nil {
+			return err
+		}
+		if key != "orders" {
+			if err := skipValue(r.dec); err != nil {
+				return err
+			}
+			continue
+		}
+		tok, err := r.dec.Token()
+		if err != nil {
+			return err
+		}
+		if tok == nil {
+			continue
+		}
+		if tok != json.Delim('[') {
+			return r.pos.errorAt(r.dec.InputOffset()-1, fmt.Errorf(`"orders" must be an array, found %v`, tok))
+		}
+		r.inArray = true
+		return nil
+	}
+
+	// no orders in the document
+	if _, err := r.dec.Token(); err != nil {
+		return err
+	}
+	r.done = true
+	return nil
+}
+
+// finish reads past the end of the orders array and the rest of the document.
+func (r *Reader) finish() error {
+	if _, err := r.dec.Token(); err != nil {
+		return err
+	}
+	r.done = true
+
+	depth := 0
+	for {
+		tok, err := r.dec.Token()
+		if err == io.EOF {
+			if depth > 0 {
+				return io.ErrUnexpectedEOF
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+}
+
+// skipValue consumes one value without keeping it in memory.
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+// position counts the bytes and newlines passing through to the decoder.
+// Only newlines after the last decoded order are kept, so memory stays small.
+type position struct {
+	r             io.Reader
+	read          int64
+	lines         int     // newlines that were forgotten
+	lastForgotten int64   // offset of the last forgotten newline
+	newlines      []int64 // offsets of newlines not yet forgotten
+}
+
+func (p *position) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	for i := 0; i < n; i++ {
+		if b[i] == '\n' {
+			p.newlines = append(p.newlines, p.read+int64(i))
+		}
+	}
+	p.read += int64(n)
+	return n, err
+}
+
+// forget drops the newlines before offset.
+func (p *position) forget(offset int64) {
+	i := sort.Search(len(p.newlines), func(i int) bool { return p.newlines[i] >= offset })
+	if i == 0 {
+		return
+	}
+	p.lines += i
+	p.lastForgotten = p.newlines[i-1]
+	p.newlines = append(p.newlines[:0], p.newlines[i:]...)
+}
+
+func (p *position) errorAt(offset int64, err error) *Error {
+	if offset < 0 {
+		offset = 0
+	}
+	i := sort.Search(len(p.newlines), func(i int) bool { return p.newlines[i] >= offset })
+	lineStart := p.lastForgotten + 1
+	if i > 0 {
+		lineStart = p.newlines[i-1] + 1
+	}
+	return &Error{
+		Line:   p.lines + i + 1,
+		Column: int(offset-lineStart) + 1,
+		Offset: offset,
+		Err:    err,
+	}
+}
+
+// MaxLineSize is the longest line a LinesReader accepts.
+const MaxLineSize = 64 * 1024 * 1024
+
+// LinesReader yields orders from JSON Lines input, one order per line.
+// Blank lines are skipped.
+type LinesReader struct {
+	scanner *bufio.Scanner
+	line    int
+	offset  int64
+	next    int64
+}
+
+func NewLinesReader(r io.Reader) *LinesReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), MaxLineSize)
+	return &LinesReader{scanner: scanner}
+}
+
+// Next returns the order on the next non-blank line, or io.EOF at the end.
+func (r *LinesReader) Next() (model.Order, error) {
+	for r.scanner.Scan() {
+		r.line++
+		r.offset = r.next
+		data := r.scanner.Bytes()
+		r.next += int64(len(data)) + 1
+		if len(bytes.TrimSpace(data)) == 0 {
+			continue
+		}
+
+		var order model.Order
+		err := json.Unmarshal(data, &order)
+		if err != nil {
+			column := 1
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &syntaxErr) {
+				column = int(syntaxErr.Offset)
+			} else if errors.As(err, &typeErr) {
+				column = int(typeErr.Offset)
+			}
+			if column < 1 {
+				column = 1
+			}
+			return model.Order{}, &Error{Line: r.line, Column: column, Offset: r.offset + int64(column) - 1, Err: err}
+		}
+		return order, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return model.Order{}, &Error{Line: r.line + 1, Column: 1, Offset: r.next, Err: err}
+	}
+	return model.Order{}, io.EOF
+}
+
+// Writer writes orders in the orders.json layout.
+type Writer struct {
+	w     io.Writer
+	count int
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+func (w *Writer) Write(order model.Order) error {
+	data, err := json.Marshal(order)
+	if err != nil {
+		return err
+	}
+	prefix := ",\n    "
+	if w.count == 0 {
+		prefix = "{\n  \"orders\": [\n    "
+	}
+	w.count++
+	_, err = fmt.Fprintf(w.w, "%s%s", prefix, data)
+	return err
+}
+
+// Close ends the orders array and the document.
+func (w *Writer) Close() error {
+	var err error
+	if w.count == 0 {
+		_, err = io.WriteString(w.w, "{\n  \"orders\": []\n}\n")
+	} else {
+		_, err = io.WriteString(w.w, "\n  ]\n}\n")
+	}
+	return err
+}
+
+// LinesWriter writes one order per line.
+type LinesWriter struct {
+	enc *json.Encoder
+}
+
+func NewLinesWriter(w io.Writer) *LinesWriter {
+	return &LinesWriter{enc: json.NewEncoder(w)}
+}
+
+func (w *LinesWriter) Write(order model.Order) error {
+	return w.enc.Encode(order)
+}
+
+func (w *LinesWriter) Close() error {
+	return nil
+}
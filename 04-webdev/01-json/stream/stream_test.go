@@ -0,0 +1,153 @@
+package stream
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"json-example/model"
+)
+
+func readAll(t *testing.T, r OrderReader) ([]model.Order, error) {
+	var orders []model.Order
+	for {
+		order, err := r.Next()
+		if err == io.EOF {
+			return orders, nil
+		}
+		if err != nil {
+			return orders, err
+		}
+		orders = append(orders, order)
+	}
+}
+
+func TestReaderOrdersFile(t *testing.T) {
+	f, err := os.Open("../orders.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	orders, err := readAll(t, NewReader(f))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orders) != 2 || orders[1].Id != 2 || len(orders[1].Items) != 2 {
+		t.Errorf("Orders were incorrect, Actual: %+v", orders)
+	}
+}
+
+func TestReaderLayouts(t *testing.T) {
+	tests := []struct {
+		in  string
+		ids []int
+	}{
+		{`{"orders": [{"id": 1}, {"id": 2}]}`, []int{1, 2}},
+		{`{"meta": {"skip": [1, {"a": 2}]}, "orders": [{"id": 3}], "count": 1}`, []int{3}},
+		{`[{"id": 4}, {"id": 5}]`, []int{4, 5}},
+		{`{"orders": []}`, nil},
+		{`{"other": 1}`, nil},
+		{`{"orders": null}`, nil},
+	}
+	for _, test := range tests {
+		orders, err := readAll(t, NewReader(strings.NewReader(test.in)))
+		if err != nil {
+			t.Errorf("%s: %v", test.in, err)
+			continue
+		}
+		var ids []int
+		for _, o := range orders {
+			ids = append(ids, o.Id)
+		}
+		if !reflect.DeepEqual(ids, test.ids) {
+			t.Errorf("%s: Actual: %v, Expected: %v", test.in, ids, test.ids)
+		}
+	}
+}
+
+func TestReaderErrorPosition(t *testing.T) {
+	tests := []struct {
+		in           string
+		line, column int
+	}{
+		{"{\"orders\": [\n  {\"id\": 1},\n  {\"id\": x}\n]}", 3, 10},
+		{"{\"orders\": [\n  {\"id\": 1},\n  {\"id\": \"one\"}\n]}", 3, 14},
+		{"{\"orders\": 5}", 1, 12},
+		{"{\"orders\": [\n{\"id\": 1}", 2, 10},
+		{"{\"orders\": [\n  {\"id\": 1},\n  {\"id\": 2, \"items\": [{\"total\": \"1.0.0\"}]},\n  {\"id\": 3}\n]}", 3, 3},
+	}
+	for _, test := range tests {
+		_, err := readAll(t, NewReader(strings.NewReader(test.in)))
+		var posErr *Error
+		if !errors.As(err, &posErr) {
+			t.Errorf("%q: expected a position error, Actual: %v", test.in, err)
+			continue
+		}
+		if posErr.Line != test.line || posErr.Column != test.column {
+			t.Errorf("%q: Actual: line %d column %d, Expected: line %d column %d (%v)",
+				test.in, posErr.Line, posErr.Column, test.line, test.column, posErr)
+		}
+	}
+}
+
+func TestLinesRoundTrip(t *testing.T) {
+	f, err := os.Open("../orders.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	orders, err := readAll(t, NewReader(f))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	w := NewLinesWriter(&buf)
+	for _, o := range orders {
+		if err := w.Write(o); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != len(orders) {
+		t.Errorf("Line count was incorrect, Actual: %d, Expected: %d", lines, len(orders))
+	}
+
+	again, err := readAll(t, NewLinesReader(&buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(orders, again) {
+		t.Errorf("JSON Lines round trip was lossy, Actual: %+v, Expected: %+v", again, orders)
+	}
+
+	buf.Reset()
+	doc := NewWriter(&buf)
+	for _, o := range orders {
+		doc.Write(o)
+	}
+	doc.Close()
+	again, err = readAll(t, NewReader(&buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(orders, again) {
+		t.Errorf("Document round trip was lossy, Actual: %+v, Expected: %+v", again, orders)
+	}
+}
+
+func TestLinesErrorPosition(t *testing.T) {
+	in := "{\"id\": 1}\n\n{\"id\": 2,}\n"
+	_, err := readAll(t, NewLinesReader(strings.NewReader(in)))
+	var posErr *Error
+	if !errors.As(err, &posErr) {
+		t.Fatalf("expected a position error, Actual: %v", err)
+	}
+	if posErr.Line != 3 || posErr.Column != 10 || posErr.Offset != 20 {
+		t.Errorf("Actual: %v, Expected: line 3, column 10, offset 20", posErr)
+	}
+}
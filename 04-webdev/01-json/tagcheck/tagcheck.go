@@ -0,0 +1,326 @@
+// Package tagcheck finds malformed struct tags, such as `json: "name"`,
+// that encoding/json silently ignores.
+package tagcheck
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Finding describes one problem with a struct field tag.
+type Finding struct {
+	Pos   token.Position
+	Field string
+	Tag   string
+	Msg   string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: struct field %s tag `%s`: %s", f.Pos, f.Field, f.Tag, f.Msg)
+}
+
+// options that encoding/json understands after the name, with the Go
+// version that added them, older versions silently ignore them
+var jsonOptions = map[string]string{
+	"omitempty": "1.0",
+	"omitzero":  "1.24",
+	"string":    "1.0",
+}
+
+// CheckFile reports malformed tags in every struct type of a parsed file,
+// including anonymous structs. goVersion is the go line of the module the
+// file belongs to, like "1.21", options it doesn't know yet are reported.
+// An empty goVersion accepts every option.
+func CheckFile(fset *token.FileSet, file *ast.File, goVersion string) []Finding {
+	var findings []Finding
+	ast.Inspect(file, func(n ast.Node) bool {
+		st, ok := n.(*ast.StructType)
+		if !ok {
+			return true
+		}
+		findings = append(findings, checkStruct(fset, st, goVersion)...)
+		return true
+	})
+	return findings
+}
+
+func checkStruct(fset *token.FileSet, st *ast.StructType, goVersion string) []Finding {
+	var findings []Finding
+	seen := make(map[string]string)
+
+	for _, field := range st.Fields.List {
+		if field.Tag == nil {
+			continue
+		}
+		name := fieldName(field)
+		tag, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			continue
+		}
+		report := func(msg string) {
+			findings = append(findings, Finding{
+				Pos:   fset.Position(field.Tag.Pos()),
+				Field: name,
+				Tag:   tag,
+				Msg:   msg,
+			})
+		}
+
+		values, err := parseTag(tag)
+		if err != nil {
+			report(err.Error())
+			continue
+		}
+		jsonTag, ok := values["json"]
+		if !ok {
+			continue
+		}
+		jsonName, msg := checkJSON(jsonTag, goVersion)
+		if msg != "" {
+			report(msg)
+			continue
+		}
+		if jsonName == "" || jsonName == "-" {
+			continue
+		}
+		if other, ok := seen[jsonName]; ok {
+			report(fmt.Sprintf("json name %q repeats the one on field %s", jsonName, other))
+			continue
+		}
+		seen[jsonName] = name
+	}
+	return findings
+}
+
+func fieldName(field *ast.Field) string {
+	if len(field.Names) > 0 {
+		return field.Names[0].Name
+	}
+	// embedded field, use the type name
+	expr := field.Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	}
+	return "_"
+}
+
+// parseTag splits a tag into its key:"value" pairs, following the
+// conventional format that reflect.StructTag.Get expects.
+func parseTag(tag string) (map[string]string, error) {
+	values := make(map[string]string)
+	for n := 0; tag != ""; n++ {
+		if n > 0 && tag[0] != ' ' {
+			return nil, fmt.Errorf("key:\"value\" pairs not separated by spaces")
+		}
+		tag = strings.TrimLeft(tag, " ")
+		if tag == "" {
+			break
+		}
+
+		i := 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 {
+			return nil, fmt.Errorf("bad syntax for struct tag key")
+		}
+		key := tag[:i]
+		if i >= len(tag) || tag[i] != ':' {
+			return nil, fmt.Errorf("bad syntax for struct tag pair, %q is missing a colon", key)
+		}
+		if i+1 >= len(tag) || tag[i+1] != '"' {
+			if i+1 < len(tag) && tag[i+1] == ' ' {
+				return nil, fmt.Errorf("bad syntax for struct tag value, remove the space after %q", key+":")
+			}
+			return nil, fmt.Errorf("bad syntax for struct tag value, the value of %q must be quoted", key)
+		}
+		tag = tag[i+1:]
+
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			return nil, fmt.Errorf("bad syntax for struct tag value, unterminated quote for %q", key)
+		}
+		value, err := strconv.Unquote(tag[:i+1])
+		if err != nil {
+			return nil, fmt.Errorf("bad syntax for struct tag value for %q", key)
+		}
+		tag = tag[i+1:]
+
+		if _, ok := values[key]; ok {
+			return nil, fmt.Errorf("duplicate struct tag key %q", key)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// checkJSON validates the value of a json tag and returns the field name it declares.
+func checkJSON(value, goVersion string) (string, string) {
+	parts := strings.Split(value, ",")
+	name := parts[0]
+	if name != strings.TrimSpace(name) {
+		return "", fmt.Sprintf("json name %q has surrounding spaces", name)
+	}
+	if name != "-" && !validJSONName(name) {
+		return "", fmt.Sprintf("json name %q contains characters encoding/json does not allow", name)
+	}
+	for _, opt := range parts[1:] {
+		if opt == "" {
+			// encoding/json skips empty options, `json:"-,"` names the field "-"
+			continue
+		}
+		since, ok := jsonOptions[opt]
+		if !ok {
+			return "", fmt.Sprintf("unknown json option %q", opt)
+		}
+		if goVersion != "" && versionLess(goVersion, since) {
+			return "", fmt.Sprintf("json option %q needs Go %s, the module is on go %s", opt, since, goVersion)
+		}
+	}
+	return name, ""
+}
+
+// versionLess reports whether Go version a, like "1.21" or "1.21.3", is older than b.
+func versionLess(a, b string) bool {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		x, _ := strconv.Atoi(as[i])
+		y, _ := strconv.Atoi(bs[i])
+		if x != y {
+			return x < y
+		}
+	}
+	return len(as) < len(bs)
+}
+
+// validJSONName mirrors the check encoding/json applies before using a tag name.
+func validJSONName(s string) bool {
+	for _, c := range s {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", c):
+		case !unicode.IsLetter(c) && !unicode.IsDigit(c):
+			return false
+		}
+	}
+	return true
+}
+
+// Check parses the Go files matched by patterns and reports their malformed tags.
+// A pattern is a file, a directory, or a directory followed by "/..." to
+// include every directory below it.
+func Check(patterns []string) ([]Finding, error) {
+	files, err := expand(patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	versions := make(map[string]string)
+	var findings []Finding
+	for _, path := range files {
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, CheckFile(fset, file, moduleGoVersion(filepath.Dir(path), versions))...)
+	}
+	return findings, nil
+}
+
+// moduleGoVersion returns the go line of the go.mod in dir or the closest
+// directory above it, "" if there's none. Results are kept in cache.
+func moduleGoVersion(dir string, cache map[string]string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	if v, ok := cache[dir]; ok {
+		return v
+	}
+	version := ""
+	if data, err := os.ReadFile(filepath.Join(dir, "go.mod")); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			if fields := strings.Fields(line); len(fields) == 2 && fields[0] == "go" {
+				version = fields[1]
+				break
+			}
+		}
+	} else if parent := filepath.Dir(dir); parent != dir {
+		version = moduleGoVersion(parent, cache)
+	}
+	cache[dir] = version
+	return version
+}
+
+func expand(patterns []string) ([]string, error) {
+	var files []string
+	for _, pattern := range patterns {
+		recursive := false
+		if pattern == "..." || strings.HasSuffix(pattern, "/...") {
+			recursive = true
+			pattern = strings.TrimSuffix(strings.TrimSuffix(pattern, "..."), "/")
+			if pattern == "" {
+				pattern = "."
+			}
+		}
+
+		info, err := os.Stat(pattern)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, pattern)
+			continue
+		}
+
+		err = filepath.WalkDir(pattern, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if path == pattern {
+					return nil
+				}
+				if !recursive || skipDir(d.Name()) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if strings.HasSuffix(path, ".go") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// skipDir follows the go tool and ignores these directories when walking.
+func skipDir(name string) bool {
+	return name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
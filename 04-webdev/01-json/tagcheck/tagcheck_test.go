@@ -0,0 +1,101 @@
+package tagcheck
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func check(t *testing.T, src, goVersion string) []Finding {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "x.go", "package x\n"+src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return CheckFile(fset, file, goVersion)
+}
+
+func TestCheckFile(t *testing.T) {
+	tests := []struct {
+		src       string
+		goVersion string
+		want      string
+	}{
+		{"type T struct { Name string `json:\"name,omitempty\"` }", "", ""},
+		{"type T struct { Name string `json:\"-\"`; Other string `json:\"-\"` }", "", ""},
+		{"type T struct { Name string `json:\",omitempty\" xml:\"name\"` }", "", ""},
+		{"type T struct { Name string `json: \"name\"` }", "", "remove the space"},
+		{"type T struct { Items []int `json: items` }", "", "remove the space"},
+		{"type T struct { Items []int `json:items` }", "", "must be quoted"},
+		{"type T struct { Name string `json:\"name\"xml:\"name\"` }", "", "not separated by spaces"},
+		{"type T struct { Name string `json` }", "", "missing a colon"},
+		{"type T struct { Name string `json:\"name,omitEmpty\"` }", "", "unknown json option"},
+		{"type T struct { Name string `json:\" name\"` }", "", "surrounding spaces"},
+		{"type T struct { A string `json:\"a\"`; B string `json:\"a\"` }", "", "repeats"},
+		{"type T struct { Name string `json:\"名前\"`; Price int `json:\"prix_ét\"` }", "", ""},
+		{"type T struct { Price int `json:\"price€\"` }", "", "does not allow"},
+		{"type T struct { Name string `json:\"a→b\"` }", "", "does not allow"},
+		{"var x = struct { Name string `json: \"name\"` }{}", "", "remove the space"},
+		{"type T struct { Name string `json:\"name,\"` }", "", ""},
+		{"type T struct { Name string `json:\"name,,omitempty\"` }", "", ""},
+		{"type T struct { Name string `json:\"-,\"` }", "", ""},
+		{"type T struct { Name string `json:\"name,omitzero\"` }", "", ""},
+		{"type T struct { Name string `json:\"name,omitzero\"` }", "1.24", ""},
+		{"type T struct { Name string `json:\"name,omitzero\"` }", "1.21", "needs Go 1.24"},
+		{"type T struct { Name string `json:\"name,omitempty\"` }", "1.16", ""},
+	}
+	for _, test := range tests {
+		findings := check(t, test.src, test.goVersion)
+		if test.want == "" {
+			if len(findings) != 0 {
+				t.Errorf("%s: unexpected findings %v", test.src, findings)
+			}
+			continue
+		}
+		if len(findings) != 1 || !strings.Contains(findings[0].Msg, test.want) {
+			t.Errorf("%s: Actual: %v, Expected a finding containing %q", test.src, findings, test.want)
+		}
+	}
+}
+
+func TestCheckUsesModuleGoVersion(t *testing.T) {
+	dir := t.TempDir()
+	src := "package x\n\ntype T struct {\n\tName string `json:\"name,omitzero\"`\n}\n"
+	if err := os.MkdirAll(filepath.Join(dir, "model"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "model", "model.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		goMod string
+		want  int
+	}{
+		{"module x\n\ngo 1.18\n", 1},
+		{"module x\n\ngo 1.24.0\n", 0},
+	} {
+		if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(test.goMod), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		findings, err := Check([]string{dir + "/..."})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(findings) != test.want {
+			t.Errorf("Check with %q was incorrect, Actual: %v, Expected: %d findings", test.goMod, findings, test.want)
+		}
+	}
+}
+
+func TestCheckRepo(t *testing.T) {
+	findings, err := Check([]string{"../..."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range findings {
+		t.Errorf("%v", f)
+	}
+}
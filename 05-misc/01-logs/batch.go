@@ -1,33 +1,83 @@
+//go:build ignore
+
+// Run with: go run batch.go [-keep] [directory or pattern, defaults to in/*.csv]
 package main
 
 import (
-	"io/ioutil"
-	"log"
+	"context"
+	"encoding/csv"
+	"flag"
+	"io"
+	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
+
+	"logs/batch"
+	"logs/logger"
+	"logs/rotate"
 )
 
-func ProcessFile(path string) {
-	filebuffer, err := ioutil.ReadFile(path)
+// ProcessFile reads a CSV file of items and quantities.
+func ProcessFile(ctx context.Context, path string, log *slog.Logger) error {
+	f, err := os.Open(path)
 	if err != nil {
-		log.Fatal("Error: ", err)
+		return err
 	}
-	inputdata := string(filebuffer)
-	log.Print("Do something with input: \n", inputdata)
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return err
+	}
+	log.Debug("Do something with input", "records", len(records))
+	return nil
 }
 
 func main() {
-	fileName := "record.csv"
+	keep := flag.Bool("keep", false, "leave the files, don't move them to done/ and failed/")
+	workers := flag.Int("workers", 0, "files processed at the same time, defaults to the number of CPUs")
+	flag.Parse()
+	// a folder of its own, so the files next to this one aren't moved
+	pattern := "in/*.csv"
+	if flag.NArg() > 0 {
+		pattern = flag.Arg(0)
+	}
 	logFile := "logfile"
 
-	f, err := os.OpenFile(logFile, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
-
+	// a new file every day or every MB, the last week of files is kept gzipped
+	f, err := rotate.Open(logFile, rotate.Options{MaxSize: 1 << 20, Daily: true, MaxBackups: 7, Compress: true})
 	if err != nil {
-		log.Fatal("Could not log to file: ", logFile)
+		slog.Error("Could not log to file", "path", logFile, "err", err)
+		os.Exit(1)
 	}
 	defer f.Close()
+	stop := f.ReopenOn(syscall.SIGHUP)
+	defer stop()
+
+	logs := logger.New(logger.Options{Format: "json", Sinks: []io.Writer{os.Stderr, f}})
+	logs.SetLevel("batch", slog.LevelDebug)
+	log := logs.For("batch")
 
-	log.SetOutput(f)
+	// Ctrl+C stops the run, the checkpoint lets the next run continue
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
 
-	log.Printf("processing file '%s' \n", fileName)
-	ProcessFile(fileName)
+	log.Info("processing files", "pattern", pattern)
+	report, err := batch.Run(ctx, pattern, batch.HandlerFunc(ProcessFile), batch.Options{
+		Workers:    *workers,
+		KeepFiles:  *keep,
+		Checkpoint: "batch.checkpoint",
+		Logger:     log,
+	})
+	if report != nil {
+		report.WriteText(os.Stdout)
+	}
+	if err != nil {
+		log.Error("batch stopped", "err", err)
+	}
+	if err != nil || (report != nil && report.Failed > 0) {
+		f.Close()
+		os.Exit(1)
+	}
 }
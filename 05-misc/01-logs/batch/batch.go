@@ -0,0 +1,228 @@
+// Package batch processes a set of input files concurrently. Every file is
+// moved to a done or failed folder afterwards, and a checkpoint file lets
+// an interrupted run pick up where it stopped.
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Handler processes one input file. Returning an error marks the file as failed,
+// the other files are still processed.
+type Handler interface {
+	Process(ctx context.Context, path string, log *slog.Logger) error
+}
+
+type HandlerFunc func(ctx context.Context, path string, log *slog.Logger) error
+
+func (f HandlerFunc) Process(ctx context.Context, path string, log *slog.Logger) error {
+	return f(ctx, path, log)
+}
+
+type Options struct {
+	Workers    int          // files processed at the same time, defaults to the number of CPUs
+	DoneDir    string       // where processed files go, relative to the file's directory, defaults to "done"
+	FailedDir  string       // where failed files go, defaults to "failed"
+	KeepFiles  bool         // leave the files where they are
+	Checkpoint string       // file recording finished files, so a new run skips them, "" turns it off
+	Logger     *slog.Logger // defaults to slog.Default()
+}
+
+const (
+	StatusDone    = "done"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped" // finished in an earlier run, according to the checkpoint
+)
+
+// Inputs lists the files of a directory, or the files matching a glob like "in/*.csv".
+func Inputs(pattern string) ([]string, error) {
+	info, err := os.Stat(pattern)
+	if err == nil && info.IsDir() {
+		entries, err := os.ReadDir(pattern)
+		if err != nil {
+			return nil, err
+		}
+		var paths []string
+		for _, e := range entries {
+			if e.Type().IsRegular() {
+				paths = append(paths, filepath.Join(pattern, e.Name()))
+			}
+		}
+		return paths, nil
+	}
+
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("batch: bad pattern %q: %w", pattern, err)
+	}
+	var paths []string
+	for _, path := range matches {
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// Run processes the files matched by pattern with h. It fails on setup
+// problems and when ctx is cancelled, failing files only show up in the report.
+func Run(ctx context.Context, pattern string, h Handler, opts Options) (*Report, error) {
+	paths, err := Inputs(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return RunFiles(ctx, paths, h, opts)
+}
+
+// RunFiles is Run for a list of files.
+func RunFiles(ctx context.Context, paths []string, h Handler, opts Options) (*Report, error) {
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+	if opts.DoneDir == "" {
+		opts.DoneDir = StatusDone
+	}
+	if opts.FailedDir == "" {
+		opts.FailedDir = StatusFailed
+	}
+	log := opts.Logger
+	if log == nil {
+		log = slog.Default()
+	}
+
+	cp, err := openCheckpoint(opts.Checkpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer cp.close()
+
+	r := &runner{h: h, opts: opts, log: log, cp: cp}
+	report := &Report{Started: time.Now()}
+	results := make([]FileResult, len(paths))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = r.process(ctx, paths[i])
+			}
+		}()
+	}
+	sent := 0
+send:
+	for i := range paths {
+		select {
+		case jobs <- i:
+			sent++
+		case <-ctx.Done():
+			break send
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	report.Files = results[:sent]
+	report.Finished = time.Now()
+	report.count()
+
+	if ctx.Err() != nil {
+		return report, ctx.Err()
+	}
+	// the run is complete, the next one starts from scratch
+	if err := cp.remove(); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+type runner struct {
+	h    Handler
+	opts Options
+	log  *slog.Logger
+	cp   *checkpoint
+}
+
+func (r *runner) process(ctx context.Context, path string) FileResult {
+	result := FileResult{Path: path}
+	log := r.log.With("file", path)
+
+	if status, ok := r.cp.status(path); ok {
+		// finished in an earlier run, which might have stopped before moving it
+		result.Status = StatusSkipped
+		if err := r.move(path, status); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			result.Err = err.Error()
+		}
+		log.Debug("skipping file", "status", status)
+		return result
+	}
+
+	start := time.Now()
+	err := r.call(ctx, path, log)
+	result.Duration = time.Since(start)
+
+	result.Status = StatusDone
+	if err != nil {
+		result.Status = StatusFailed
+		result.Err = err.Error()
+		if ctx.Err() != nil {
+			// most likely stopped halfway, leave it for the next run
+			log.Warn("processing interrupted", "err", err)
+			return result
+		}
+		log.Error("processing failed", "err", err, "duration", result.Duration)
+	} else {
+		log.Info("processed file", "duration", result.Duration)
+	}
+
+	if err := r.cp.record(path, result.Status); err != nil {
+		log.Error("writing checkpoint failed", "err", err)
+	}
+	if err := r.move(path, result.Status); err != nil {
+		log.Error("moving file failed", "err", err)
+		if result.Err == "" {
+			result.Err = err.Error()
+		}
+	}
+	return result
+}
+
+// call runs the handler, turning a panic into an error so one bad file doesn't stop the run.
+func (r *runner) call(ctx context.Context, path string, log *slog.Logger) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return r.h.Process(ctx, path, log)
+}
+
+func (r *runner) move(path string, status string) error {
+	if r.opts.KeepFiles {
+		return nil
+	}
+	dir := r.opts.DoneDir
+	if status == StatusFailed {
+		dir = r.opts.FailedDir
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(filepath.Dir(path), dir)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return os.Rename(path, filepath.Join(dir, filepath.Base(path)))
+}
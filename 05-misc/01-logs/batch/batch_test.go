@@ -0,0 +1,141 @@
+package batch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+var quiet = slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+
+func inputs(t *testing.T, names ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// failBad fails the files with "bad" in their name.
+var failBad = HandlerFunc(func(ctx context.Context, path string, log *slog.Logger) error {
+	if strings.Contains(path, "bad") {
+		return errors.New("bad record")
+	}
+	return nil
+})
+
+func TestRunMovesFiles(t *testing.T) {
+	dir := inputs(t, "a.csv", "b.csv", "bad.csv", "notes.txt")
+	report, err := Run(context.Background(), filepath.Join(dir, "*.csv"), failBad, Options{Workers: 2, Logger: quiet})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Done != 2 || report.Failed != 1 || report.Skipped != 0 {
+		t.Errorf("Counts were incorrect, Actual: %d done, %d failed, %d skipped, Expected: 2, 1, 0", report.Done, report.Failed, report.Skipped)
+	}
+	for _, path := range []string{"done/a.csv", "done/b.csv", "failed/bad.csv", "notes.txt"} {
+		if !exists(filepath.Join(dir, path)) {
+			t.Errorf("%s is missing", path)
+		}
+	}
+	if failed := report.Errors(); len(failed) != 1 || failed[0].Err != "bad record" {
+		t.Errorf("Errors was incorrect, Actual: %v", failed)
+	}
+}
+
+func TestRunDirectoryKeepFiles(t *testing.T) {
+	dir := inputs(t, "a.csv", "b.csv")
+	var count int32
+	h := HandlerFunc(func(ctx context.Context, path string, log *slog.Logger) error {
+		atomic.AddInt32(&count, 1)
+		return nil
+	})
+	if _, err := Run(context.Background(), dir, h, Options{KeepFiles: true, Logger: quiet}); err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("Processed files, Actual: %d, Expected: %d", count, 2)
+	}
+	if !exists(filepath.Join(dir, "a.csv")) || exists(filepath.Join(dir, "done")) {
+		t.Errorf("KeepFiles should leave the files alone")
+	}
+}
+
+func TestPanicFailsFile(t *testing.T) {
+	dir := inputs(t, "a.csv")
+	h := HandlerFunc(func(ctx context.Context, path string, log *slog.Logger) error {
+		panic("oops")
+	})
+	report, err := Run(context.Background(), dir, h, Options{Logger: quiet})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Failed != 1 || report.Files[0].Err != "panic: oops" {
+		t.Errorf("A panic should fail the file, Actual: %+v", report.Files)
+	}
+}
+
+func TestResumeFromCheckpoint(t *testing.T) {
+	dir := inputs(t, "a.csv", "b.csv", "c.csv")
+	checkpoint := filepath.Join(t.TempDir(), "checkpoint")
+
+	// the first run is cancelled after one file
+	ctx, cancel := context.WithCancel(context.Background())
+	var processed []string
+	h := HandlerFunc(func(ctx context.Context, path string, log *slog.Logger) error {
+		processed = append(processed, filepath.Base(path))
+		if len(processed) == 1 {
+			defer cancel()
+		}
+		return nil
+	})
+	opts := Options{Workers: 1, KeepFiles: true, Checkpoint: checkpoint, Logger: quiet}
+	if _, err := Run(ctx, dir, h, opts); err != context.Canceled {
+		t.Fatalf("Run, Actual: %v, Expected: %v", err, context.Canceled)
+	}
+	if !exists(checkpoint) {
+		t.Fatal("an interrupted run should keep its checkpoint")
+	}
+
+	processed = nil
+	report, err := Run(context.Background(), dir, h, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(processed, ",") != "b.csv,c.csv" {
+		t.Errorf("Second run, Actual: %v, Expected: [b.csv c.csv]", processed)
+	}
+	if report.Skipped != 1 || report.Done != 2 {
+		t.Errorf("Counts were incorrect, Actual: %d skipped, %d done", report.Skipped, report.Done)
+	}
+	if exists(checkpoint) {
+		t.Errorf("a complete run should remove its checkpoint")
+	}
+}
+
+func TestReportText(t *testing.T) {
+	dir := inputs(t, "a.csv", "bad.csv")
+	report, _ := Run(context.Background(), dir, failBad, Options{Logger: quiet})
+
+	var buf bytes.Buffer
+	report.WriteText(&buf)
+	out := buf.String()
+	if !strings.Contains(out, "1 done, 1 failed, 0 skipped") || !strings.Contains(out, "bad record") {
+		t.Errorf("WriteText was incorrect, Actual: %q", out)
+	}
+}
@@ -0,0 +1,105 @@
+package batch
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// checkpoint is a file with one JSON line per finished file. Lines are
+// appended and synced right away, so a crash loses at most the files
+// being processed.
+type checkpoint struct {
+	path string
+
+	mu       sync.Mutex
+	file     *os.File
+	finished map[string]string
+}
+
+type checkpointEntry struct {
+	Path   string `json:"path"`
+	Status string `json:"status"`
+}
+
+// openCheckpoint reads the checkpoint at path, a nil checkpoint does nothing.
+func openCheckpoint(path string) (*checkpoint, error) {
+	if path == "" {
+		return nil, nil
+	}
+	cp := &checkpoint{path: path, finished: map[string]string{}}
+	if f, err := os.Open(path); err == nil {
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var e checkpointEntry
+			// a line cut off by a crash is ignored, that file is processed again
+			if json.Unmarshal(scanner.Bytes(), &e) == nil {
+				cp.finished[key(e.Path)] = e.Status
+			}
+		}
+		f.Close()
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	cp.file = f
+	return cp, nil
+}
+
+func key(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+func (cp *checkpoint) status(path string) (string, bool) {
+	if cp == nil {
+		return "", false
+	}
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	status, ok := cp.finished[key(path)]
+	return status, ok
+}
+
+func (cp *checkpoint) record(path string, status string) error {
+	if cp == nil {
+		return nil
+	}
+	line, err := json.Marshal(checkpointEntry{Path: path, Status: status})
+	if err != nil {
+		return err
+	}
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	cp.finished[key(path)] = status
+	if _, err := cp.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return cp.file.Sync()
+}
+
+func (cp *checkpoint) close() {
+	if cp != nil && cp.file != nil {
+		cp.file.Close()
+		cp.file = nil
+	}
+}
+
+func (cp *checkpoint) remove() error {
+	if cp == nil {
+		return nil
+	}
+	cp.close()
+	return os.Remove(cp.path)
+}
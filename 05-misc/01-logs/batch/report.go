@@ -0,0 +1,75 @@
+package batch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+type FileResult struct {
+	Path     string        `json:"path"`
+	Status   string        `json:"status"`
+	Duration time.Duration `json:"duration"`
+	Err      string        `json:"error,omitempty"`
+}
+
+// Report sums up a run.
+type Report struct {
+	Started  time.Time     `json:"started"`
+	Finished time.Time     `json:"finished"`
+	Duration time.Duration `json:"duration"`
+	Done     int           `json:"done"`
+	Failed   int           `json:"failed"`
+	Skipped  int           `json:"skipped"`
+	Files    []FileResult  `json:"files"`
+}
+
+func (r *Report) count() {
+	r.Duration = r.Finished.Sub(r.Started)
+	r.Done, r.Failed, r.Skipped = 0, 0, 0
+	for _, f := range r.Files {
+		switch f.Status {
+		case StatusDone:
+			r.Done++
+		case StatusFailed:
+			r.Failed++
+		case StatusSkipped:
+			r.Skipped++
+		}
+	}
+}
+
+// Errors returns the failed files.
+func (r *Report) Errors() []FileResult {
+	var failed []FileResult
+	for _, f := range r.Files {
+		if f.Status == StatusFailed {
+			failed = append(failed, f)
+		}
+	}
+	return failed
+}
+
+// WriteText writes the counts, and a table of the failed files if there are any.
+func (r *Report) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, "%d files in %v: %d done, %d failed, %d skipped\n",
+		len(r.Files), r.Duration.Round(time.Millisecond), r.Done, r.Failed, r.Skipped)
+	failed := r.Errors()
+	if len(failed) == 0 {
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "\nFAILED\tDURATION\tERROR")
+	for _, f := range failed {
+		fmt.Fprintf(tw, "%s\t%v\t%s\n", f.Path, f.Duration.Round(time.Millisecond), f.Err)
+	}
+	return tw.Flush()
+}
+
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
@@ -0,0 +1,218 @@
+// Command logq searches and counts the entries of log files.
+//
+//	logq -level error -since 1h logfile
+//	logq -grep "divide" -count message testlogfile
+//	logq -f -level warn logfile
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"logs/logq"
+)
+
+func main() {
+	since := flag.String("since", "", `keep entries from this time on, like "2022-03-28 14:00" or "1h" for the last hour`)
+	until := flag.String("until", "", "keep entries before this time")
+	level := flag.String("level", "", "keep entries at this level or above: debug, info, warn or error")
+	grep := flag.String("grep", "", "keep entries matching this regular expression")
+	ignoreCase := flag.Bool("i", false, "match -grep ignoring case")
+	count := flag.String("count", "", `count entries per "message" or per "minute" instead of printing them`)
+	follow := flag.Bool("f", false, "keep printing entries as they are written, like tail -f")
+	asJSON := flag.Bool("json", false, "print entries or counts as JSON")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: logq [flags] [file ...]\n\nReads stdin without files, .gz files are decompressed.\n\nFlags:")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	filter, err := buildFilter(*since, *until, *level, *grep, *ignoreCase)
+	if err != nil {
+		fail(err)
+	}
+	var counter *logq.Counter
+	switch *count {
+	case "":
+	case "message":
+		counter = logq.NewCounter(logq.ByMessage)
+	case "minute":
+		counter = logq.NewCounter(logq.ByMinute)
+	default:
+		fail(fmt.Errorf(`-count takes "message" or "minute", not %q`, *count))
+	}
+
+	out := &printer{w: os.Stdout, json: *asJSON}
+	if *follow {
+		if flag.NArg() != 1 || counter != nil {
+			fail(fmt.Errorf("-f follows exactly one file and can't be combined with -count"))
+		}
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+		if err := followFile(ctx, flag.Arg(0), filter, out); err != nil {
+			fail(err)
+		}
+		return
+	}
+
+	files := flag.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	for _, path := range files {
+		err := readFile(path, func(e logq.Entry) {
+			if !filter.Match(e) {
+				return
+			}
+			if counter != nil {
+				counter.Add(e)
+			} else {
+				out.entry(e)
+			}
+		})
+		if err != nil {
+			fail(err)
+		}
+	}
+
+	if counter != nil {
+		counts := counter.ByCount()
+		if *count == "minute" {
+			counts = counter.ByKey()
+		}
+		out.counts(counts)
+	}
+}
+
+func buildFilter(since, until, level, grep string, ignoreCase bool) (logq.Filter, error) {
+	var filter logq.Filter
+	var err error
+	now := time.Now()
+	if since != "" {
+		if filter.Since, err = logq.ParseTime(since, now); err != nil {
+			return filter, err
+		}
+	}
+	if until != "" {
+		if filter.Until, err = logq.ParseTime(until, now); err != nil {
+			return filter, err
+		}
+	}
+	if level != "" {
+		var l slog.Level
+		if err := l.UnmarshalText([]byte(level)); err != nil {
+			return filter, fmt.Errorf("unknown level %q, use debug, info, warn or error", level)
+		}
+		filter.MinLevel = &l
+	}
+	if grep != "" {
+		if ignoreCase {
+			grep = "(?i)" + grep
+		}
+		if filter.Pattern, err = regexp.Compile(grep); err != nil {
+			return filter, err
+		}
+	}
+	return filter, nil
+}
+
+func readFile(path string, fn func(logq.Entry)) error {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+		if strings.HasSuffix(path, ".gz") {
+			zr, err := gzip.NewReader(f)
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			r = zr
+		}
+	}
+
+	entries := logq.NewReader(r)
+	for {
+		e, err := entries.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		fn(e)
+	}
+}
+
+// followFile prints new entries of path. A line that doesn't start an entry
+// is printed if the entry it belongs to was.
+func followFile(ctx context.Context, path string, filter logq.Filter, out *printer) error {
+	printing := false
+	return logq.Follow(ctx, path, func(line string) {
+		e, ok := logq.Parse(line)
+		if !ok {
+			if printing {
+				fmt.Fprintln(out.w, line)
+			}
+			return
+		}
+		printing = filter.Match(e) && !out.json
+		if filter.Match(e) {
+			out.entry(e)
+		}
+	})
+}
+
+type printer struct {
+	w    io.Writer
+	json bool
+}
+
+func (p *printer) entry(e logq.Entry) {
+	if p.json {
+		json.NewEncoder(p.w).Encode(e)
+		return
+	}
+	fmt.Fprintln(p.w, e.Raw)
+}
+
+func (p *printer) counts(counts []logq.Count) {
+	if p.json {
+		enc := json.NewEncoder(p.w)
+		enc.SetIndent("", "  ")
+		enc.Encode(counts)
+		return
+	}
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "COUNT\tFIRST\tLAST\tKEY")
+	for _, c := range counts {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", c.Count, stamp(c.First), stamp(c.Last), c.Key)
+	}
+	tw.Flush()
+}
+
+func stamp(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "logq:", err)
+	os.Exit(2)
+}
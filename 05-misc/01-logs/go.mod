@@ -0,0 +1,7 @@
+module logs
+
+go 1.21
+
+require error-handling/errs v0.0.0
+
+replace error-handling/errs => ../../01-basics/08-error-handling/errs
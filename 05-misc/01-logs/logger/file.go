@@ -0,0 +1,8 @@
+package logger
+
+import "os"
+
+// OpenFile opens path for appending log entries, creating it if needed.
+func OpenFile(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+}
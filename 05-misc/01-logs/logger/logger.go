@@ -0,0 +1,157 @@
+// Package logger builds leveled, structured loggers on top of log/slog.
+//
+// Every package asks for its own logger with For("name"), and the level of
+// each package can be changed on its own, even while the program runs:
+//
+//	log := logger.New(logger.Options{Format: "json", Sinks: []io.Writer{os.Stderr, f}})
+//	log.SetLevel("batch", slog.LevelDebug)
+//	log.For("batch").Info("processing file", "path", path)
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+)
+
+// PackageKey is the attribute holding the package name of an entry.
+const PackageKey = "pkg"
+
+type Options struct {
+	Format    string      // "text" or "json", text is the default
+	Level     slog.Level  // level of packages without their own level, defaults to info
+	Sinks     []io.Writer // where entries go, every sink gets every entry, defaults to stderr
+	AddSource bool        // add the file and line of the call
+}
+
+// Logger hands out per package slog loggers writing to the same sinks.
+type Logger struct {
+	handler slog.Handler
+
+	mu     sync.RWMutex
+	level  slog.Level
+	levels map[string]slog.Level
+}
+
+// New creates a Logger. Unknown formats fall back to text.
+func New(opts Options) *Logger {
+	sinks := opts.Sinks
+	if len(sinks) == 0 {
+		sinks = []io.Writer{os.Stderr}
+	}
+	// the sinks let everything through, the levels are checked per package
+	handlerOpts := &slog.HandlerOptions{Level: slog.Level(-1 << 10), AddSource: opts.AddSource}
+
+	var handlers []slog.Handler
+	for _, w := range sinks {
+		if opts.Format == "json" {
+			handlers = append(handlers, slog.NewJSONHandler(w, handlerOpts))
+		} else {
+			handlers = append(handlers, slog.NewTextHandler(w, handlerOpts))
+		}
+	}
+	return &Logger{handler: Multi(handlers...), level: opts.Level, levels: map[string]slog.Level{}}
+}
+
+// For returns the logger of package pkg, its entries carry a "pkg" attribute.
+// An empty pkg gives a logger using the default level and no attribute.
+func (l *Logger) For(pkg string) *slog.Logger {
+	var h slog.Handler = &levelHandler{level: packageLevel{l, pkg}, next: l.handler}
+	if pkg != "" {
+		h = h.WithAttrs([]slog.Attr{slog.String(PackageKey, pkg)})
+	}
+	return slog.New(h)
+}
+
+// SetDefault makes the logger of the empty package the slog default, so
+// slog.Info() and the standard log package write to the same sinks.
+func (l *Logger) SetDefault() {
+	slog.SetDefault(l.For(""))
+}
+
+// SetLevel changes the level of pkg, "" changes the default level.
+func (l *Logger) SetLevel(pkg string, level slog.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if pkg == "" {
+		l.level = level
+	} else {
+		l.levels[pkg] = level
+	}
+}
+
+// Level returns the level pkg logs at.
+func (l *Logger) Level(pkg string) slog.Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if level, ok := l.levels[pkg]; ok {
+		return level
+	}
+	return l.level
+}
+
+// Configure sets levels from a spec like "warn,batch=debug,db=error", where
+// an entry without a package sets the default level. It's meant for flags
+// and environment variables.
+func (l *Logger) Configure(spec string) error {
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		pkg, name := "", part
+		if i := strings.Index(part, "="); i >= 0 {
+			pkg, name = strings.TrimSpace(part[:i]), part[i+1:]
+		}
+		level, err := ParseLevel(name)
+		if err != nil {
+			return err
+		}
+		l.SetLevel(pkg, level)
+	}
+	return nil
+}
+
+// ParseLevel reads debug, info, warn or error, in any case.
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(s))); err != nil {
+		return 0, fmt.Errorf("logger: unknown level %q, use debug, info, warn or error", s)
+	}
+	return level, nil
+}
+
+// packageLevel looks the level up on every call, so SetLevel affects loggers already handed out.
+type packageLevel struct {
+	l   *Logger
+	pkg string
+}
+
+func (p packageLevel) Level() slog.Level {
+	return p.l.Level(p.pkg)
+}
+
+type levelHandler struct {
+	level slog.Leveler
+	next  slog.Handler
+}
+
+func (h *levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level() && h.next.Enabled(ctx, level)
+}
+
+func (h *levelHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.next.Handle(ctx, r)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{level: h.level, next: h.next.WithAttrs(attrs)}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{level: h.level, next: h.next.WithGroup(name)}
+}
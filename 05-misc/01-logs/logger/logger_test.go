@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLevelPerPackage(t *testing.T) {
+	var buf bytes.Buffer
+	logs := New(Options{Sinks: []io.Writer{&buf}, Level: slog.LevelWarn})
+	logs.SetLevel("batch", slog.LevelDebug)
+
+	logs.For("batch").Debug("batch debug")
+	logs.For("db").Info("db info")
+	logs.For("db").Warn("db warn")
+
+	out := buf.String()
+	if !strings.Contains(out, "batch debug") {
+		t.Errorf("Debug entry of batch missing, Actual: %q", out)
+	}
+	if strings.Contains(out, "db info") {
+		t.Errorf("Info entry of db should be dropped, Actual: %q", out)
+	}
+	if !strings.Contains(out, "db warn") {
+		t.Errorf("Warn entry of db missing, Actual: %q", out)
+	}
+}
+
+func TestSetLevelAfterFor(t *testing.T) {
+	var buf bytes.Buffer
+	logs := New(Options{Sinks: []io.Writer{&buf}})
+	log := logs.For("batch")
+
+	log.Debug("before")
+	logs.SetLevel("batch", slog.LevelDebug)
+	log.Debug("after")
+
+	out := buf.String()
+	if strings.Contains(out, "before") || !strings.Contains(out, "after") {
+		t.Errorf("SetLevel should apply to loggers handed out earlier, Actual: %q", out)
+	}
+}
+
+func TestJSONFields(t *testing.T) {
+	var buf bytes.Buffer
+	logs := New(Options{Format: "json", Sinks: []io.Writer{&buf}})
+	logs.For("batch").With("job", 7).Error("failed", "err", errors.New("boom"))
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Output isn't JSON: %v, %q", err, buf.String())
+	}
+	expected := map[string]interface{}{"level": "ERROR", "msg": "failed", "pkg": "batch", "job": 7.0, "err": "boom"}
+	for key, value := range expected {
+		if entry[key] != value {
+			t.Errorf("Field %s was incorrect, Actual: %v, Expected: %v", key, entry[key], value)
+		}
+	}
+}
+
+func TestMultipleSinks(t *testing.T) {
+	var terminal, file bytes.Buffer
+	logs := New(Options{Sinks: []io.Writer{&terminal, &file}})
+	logs.For("").Info("hello", "n", 1)
+
+	if terminal.String() == "" || terminal.String() != file.String() {
+		t.Errorf("Both sinks should get the entry, Actual: %q and %q", terminal.String(), file.String())
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	logs := New(Options{})
+	if err := logs.Configure("warn, batch=debug,db=ERROR"); err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]slog.Level{"": slog.LevelWarn, "other": slog.LevelWarn, "batch": slog.LevelDebug, "db": slog.LevelError}
+	for pkg, expected := range cases {
+		if actual := logs.Level(pkg); actual != expected {
+			t.Errorf("Level of %q was incorrect, Actual: %v, Expected: %v", pkg, actual, expected)
+		}
+	}
+
+	if err := logs.Configure("batch=loud"); err == nil {
+		t.Errorf("Configure should reject unknown levels")
+	}
+}
@@ -0,0 +1,88 @@
+package logq
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Count is how often a key occurs.
+type Count struct {
+	Key   string    `json:"key"`
+	Count int       `json:"count"`
+	First time.Time `json:"first"`
+	Last  time.Time `json:"last"`
+}
+
+// Counter groups entries by a key, like their message or their minute.
+type Counter struct {
+	Key    func(Entry) string
+	counts map[string]*Count
+	order  []string
+}
+
+var digits = regexp.MustCompile(`[0-9]+`)
+
+// ByMessage groups entries by the first line of their message, with numbers
+// replaced by #, so "took 13ms" and "took 20ms" end up together.
+func ByMessage(e Entry) string {
+	msg := e.Msg
+	if i := strings.IndexByte(msg, '\n'); i >= 0 {
+		msg = msg[:i]
+	}
+	return digits.ReplaceAllString(msg, "#")
+}
+
+// ByMinute groups entries by the minute they were written in.
+func ByMinute(e Entry) string {
+	if e.Time.IsZero() {
+		return "unknown"
+	}
+	return e.Time.Truncate(time.Minute).Format("2006-01-02 15:04")
+}
+
+func NewCounter(key func(Entry) string) *Counter {
+	return &Counter{Key: key, counts: map[string]*Count{}}
+}
+
+func (c *Counter) Add(e Entry) {
+	key := c.Key(e)
+	count, ok := c.counts[key]
+	if !ok {
+		count = &Count{Key: key, First: e.Time}
+		c.counts[key] = count
+		c.order = append(c.order, key)
+	}
+	count.Count++
+	if !e.Time.IsZero() {
+		if count.First.IsZero() || e.Time.Before(count.First) {
+			count.First = e.Time
+		}
+		if e.Time.After(count.Last) {
+			count.Last = e.Time
+		}
+	}
+}
+
+// ByCount lists the most frequent keys first.
+func (c *Counter) ByCount() []Count {
+	list := c.list()
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Count > list[j].Count })
+	return list
+}
+
+// ByKey lists the keys in order, which for ByMinute is oldest first.
+func (c *Counter) ByKey() []Count {
+	list := c.list()
+	sort.Slice(list, func(i, j int) bool { return list[i].Key < list[j].Key })
+	return list
+}
+
+func (c *Counter) list() []Count {
+	list := make([]Count, 0, len(c.order))
+	for _, key := range c.order {
+		list = append(list, *c.counts[key])
+	}
+	return list
+}
@@ -0,0 +1,56 @@
+package logq
+
+import (
+	"log/slog"
+	"regexp"
+	"time"
+)
+
+// Filter decides which entries to keep, its zero value keeps all.
+type Filter struct {
+	Since    time.Time // keep entries at or after Since
+	Until    time.Time // keep entries before Until
+	MinLevel *slog.Level
+	Pattern  *regexp.Regexp // matched against the whole entry, fields included
+}
+
+// Match reports whether e passes the filter. Entries without a time are
+// dropped as soon as Since or Until is set.
+func (f Filter) Match(e Entry) bool {
+	if !f.Since.IsZero() || !f.Until.IsZero() {
+		if e.Time.IsZero() {
+			return false
+		}
+		if !f.Since.IsZero() && e.Time.Before(f.Since) {
+			return false
+		}
+		if !f.Until.IsZero() && !e.Time.Before(f.Until) {
+			return false
+		}
+	}
+	if f.MinLevel != nil && e.Level < *f.MinLevel {
+		return false
+	}
+	if f.Pattern != nil && !f.Pattern.MatchString(e.Raw) {
+		return false
+	}
+	return true
+}
+
+// ParseTime reads times for Since and Until: RFC 3339, "2006-01-02 15:04:05",
+// "2006-01-02", the standard log format, or a duration like "1h" meaning that
+// long before now.
+func ParseTime(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006/01/02 15:04:05", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, &time.ParseError{Layout: time.RFC3339, Value: s, Message: `: use a time like "2022-03-28 14:11" or a duration like "1h"`}
+}
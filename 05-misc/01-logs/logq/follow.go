@@ -0,0 +1,79 @@
+package logq
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"time"
+)
+
+// PollInterval is how often Follow checks the file for new lines.
+var PollInterval = 250 * time.Millisecond
+
+// Follow calls fn for every line appended to the file at path, like tail -f.
+// It starts at the end of the file, and starts over when the file is
+// truncated or replaced, which is what rotation does. It returns when ctx is done.
+func Follow(ctx context.Context, path string, fn func(line string)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { f.Close() }()
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+
+	var partial []byte
+	buf := make([]byte, 32*1024)
+	ticker := time.NewTicker(PollInterval)
+	defer ticker.Stop()
+	for {
+		for {
+			n, err := f.Read(buf)
+			offset += int64(n)
+			partial = append(partial, buf[:n]...)
+			for {
+				i := bytes.IndexByte(partial, '\n')
+				if i < 0 {
+					break
+				}
+				fn(string(partial[:i]))
+				partial = partial[i+1:]
+			}
+			if err != nil && err != io.EOF {
+				return err
+			}
+			if err == io.EOF || n == 0 {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if replaced(f, path, offset) {
+			if g, err := os.Open(path); err == nil {
+				f.Close()
+				f, offset, partial = g, 0, nil
+			}
+		}
+	}
+}
+
+// replaced reports whether path is now another file than f, or shorter than what was read.
+func replaced(f *os.File, path string, offset int64) bool {
+	current, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	opened, err := f.Stat()
+	if err != nil {
+		return true
+	}
+	return !os.SameFile(current, opened) || current.Size() < offset
+}
@@ -0,0 +1,209 @@
+package logq
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const mixed = `2022/03/28 14:11:24 processing file 'record.csv'
+2022/03/28 14:11:24 Error: open record.csv: no such file or directory
+2022/03/28 14:12:01 can't divide by 0 goroutine 1 [running]:
+runtime/debug.Stack()
+	/book/panic.go:14 +0x5b
+{"time":"2022-03-28T14:13:00Z","level":"WARN","msg":"slow file","pkg":"batch","ms":1200}
+time=2022-03-28T14:14:00.000Z level=DEBUG msg="Do something with input" pkg=batch records=2
+`
+
+func readAll(t *testing.T, log string) []Entry {
+	t.Helper()
+	var entries []Entry
+	r := NewReader(strings.NewReader(log))
+	for {
+		e, err := r.Next()
+		if err == io.EOF {
+			return entries
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, e)
+	}
+}
+
+func TestReader(t *testing.T) {
+	entries := readAll(t, mixed)
+	if len(entries) != 5 {
+		t.Fatalf("Entry count, Actual: %d, Expected: %d", len(entries), 5)
+	}
+
+	cases := []struct {
+		msg   string
+		level slog.Level
+		line  int
+	}{
+		{"processing file 'record.csv'", slog.LevelInfo, 1},
+		{"Error: open record.csv: no such file or directory", slog.LevelError, 2},
+		{"can't divide by 0 goroutine 1 [running]:\nruntime/debug.Stack()\n\t/book/panic.go:14 +0x5b", slog.LevelError, 3},
+		{"slow file", slog.LevelWarn, 6},
+		{"Do something with input", slog.LevelDebug, 7},
+	}
+	for i, c := range cases {
+		e := entries[i]
+		if e.Msg != c.msg || e.Level != c.level || e.Line != c.line {
+			t.Errorf("Entry %d was incorrect, Actual: %q %v line %d, Expected: %q %v line %d", i, e.Msg, e.Level, e.Line, c.msg, c.level, c.line)
+		}
+	}
+
+	if entries[3].Fields["ms"] != "1200" || entries[4].Fields["records"] != "2" || entries[4].Fields["pkg"] != "batch" {
+		t.Errorf("Fields were incorrect, Actual: %v and %v", entries[3].Fields, entries[4].Fields)
+	}
+	expected := time.Date(2022, 3, 28, 14, 14, 0, 0, time.UTC)
+	if !entries[4].Time.Equal(expected) {
+		t.Errorf("Time was incorrect, Actual: %v, Expected: %v", entries[4].Time, expected)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	entries := readAll(t, mixed)
+	warn := slog.LevelWarn
+	since, _ := ParseTime("2022-03-28T14:12:00Z", time.Now())
+
+	cases := []struct {
+		name     string
+		filter   Filter
+		expected int
+	}{
+		{"all", Filter{}, 5},
+		{"level", Filter{MinLevel: &warn}, 3},
+		{"since", Filter{Since: since}, 3},
+		{"until", Filter{Until: since}, 2},
+		{"pattern in fields", Filter{Pattern: regexp.MustCompile(`pkg=batch|"pkg":"batch"`)}, 2},
+		{"pattern in stack", Filter{Pattern: regexp.MustCompile(`panic\.go`)}, 1},
+	}
+	for _, c := range cases {
+		n := 0
+		for _, e := range entries {
+			if c.filter.Match(e) {
+				n++
+			}
+		}
+		if n != c.expected {
+			t.Errorf("Filter %s, Actual: %d, Expected: %d", c.name, n, c.expected)
+		}
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2022, 3, 28, 15, 0, 0, 0, time.Local)
+	cases := map[string]time.Time{
+		"1h":                  time.Date(2022, 3, 28, 14, 0, 0, 0, time.Local),
+		"2022-03-28 14:11":    time.Date(2022, 3, 28, 14, 11, 0, 0, time.Local),
+		"2022/03/28 14:11:24": time.Date(2022, 3, 28, 14, 11, 24, 0, time.Local),
+		"2022-03-28":          time.Date(2022, 3, 28, 0, 0, 0, 0, time.Local),
+	}
+	for s, expected := range cases {
+		actual, err := ParseTime(s, now)
+		if err != nil || !actual.Equal(expected) {
+			t.Errorf("ParseTime(%q), Actual: %v %v, Expected: %v", s, actual, err, expected)
+		}
+	}
+	if _, err := ParseTime("yesterday", now); err == nil {
+		t.Errorf("ParseTime should reject %q", "yesterday")
+	}
+}
+
+func TestCounter(t *testing.T) {
+	log := `2022/03/28 14:11:24 took 13ms
+2022/03/28 14:11:50 took 20ms
+2022/03/28 14:12:10 done
+`
+	byMessage := NewCounter(ByMessage)
+	byMinute := NewCounter(ByMinute)
+	for _, e := range readAll(t, log) {
+		byMessage.Add(e)
+		byMinute.Add(e)
+	}
+
+	counts := byMessage.ByCount()
+	if len(counts) != 2 || counts[0].Key != "took #ms" || counts[0].Count != 2 {
+		t.Errorf("ByMessage was incorrect, Actual: %+v", counts)
+	}
+	minutes := byMinute.ByKey()
+	if len(minutes) != 2 || minutes[0].Key != "2022-03-28 14:11" || minutes[0].Count != 2 || minutes[1].Count != 1 {
+		t.Errorf("ByMinute was incorrect, Actual: %+v", minutes)
+	}
+}
+
+func TestFollow(t *testing.T) {
+	PollInterval = 10 * time.Millisecond
+	path := filepath.Join(t.TempDir(), "logfile")
+	os.WriteFile(path, []byte("2022/03/28 14:11:24 old line\n"), 0644)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var mu sync.Mutex
+	var lines []string
+	done := make(chan error)
+	go func() {
+		done <- Follow(ctx, path, func(line string) {
+			mu.Lock()
+			lines = append(lines, line)
+			mu.Unlock()
+		})
+	}()
+	waitFor := func(n int) {
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			mu.Lock()
+			got := len(lines)
+			mu.Unlock()
+			if got >= n {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("Follow got %d lines, Expected: %d", got, n)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	f, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	f.WriteString("new ")
+	f.WriteString("line\n")
+	f.Close()
+	waitFor(1)
+
+	// rotated: the file is moved away and a new one takes its place
+	os.Rename(path, path+".1")
+	os.WriteFile(path, []byte("after rotation\n"), 0644)
+	waitFor(2)
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if strings.Join(lines, "|") != "new line|after rotation" {
+		t.Errorf("Follow, Actual: %q, Expected: %q", lines, []string{"new line", "after rotation"})
+	}
+}
+
+func TestFollowReportsReadErrors(t *testing.T) {
+	// reading a directory fails, that error must not be taken for "no new lines"
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := Follow(ctx, t.TempDir(), func(string) {})
+	if err == nil || ctx.Err() != nil {
+		t.Errorf("Follow on a directory was incorrect, Actual: %v, Expected: a read error", err)
+	}
+}
@@ -0,0 +1,185 @@
+// Package logq reads log files written by the standard log package or by
+// log/slog, as text or JSON, and filters and counts their entries.
+package logq
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Entry is one log entry. Lines without a timestamp, like a stack trace,
+// belong to the entry before them.
+type Entry struct {
+	Time   time.Time         `json:"time"`
+	Level  slog.Level        `json:"level"`
+	Msg    string            `json:"msg"`
+	Fields map[string]string `json:"fields,omitempty"`
+	Line   int               `json:"line"` // where the entry starts, counting from 1
+	Raw    string            `json:"-"`
+
+	guessLevel bool // no level in the log, it's guessed from the message
+}
+
+// the standard log package writes 2022/03/28 14:11:24, maybe with microseconds
+var stdPrefix = regexp.MustCompile(`^(\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}(?:\.\d+)?) ?(.*)$`)
+
+// the standard log package has no levels, these words or a stack trace in a message mean something went wrong
+var errorWords = regexp.MustCompile(`(?i)\b(error|fatal|panic|failed)\b|goroutine \d+ \[`)
+
+// Parse reads a single line, it returns false for lines that don't start an entry.
+func Parse(line string) (Entry, bool) {
+	line = strings.TrimRight(line, "\r\n")
+	switch {
+	case strings.HasPrefix(line, "{"):
+		return parseJSON(line)
+	case strings.HasPrefix(line, "time="):
+		return parseText(line)
+	}
+	if m := stdPrefix.FindStringSubmatch(line); m != nil {
+		t, err := time.ParseInLocation("2006/01/02 15:04:05", m[1], time.Local)
+		if err != nil {
+			return Entry{}, false
+		}
+		e := Entry{Time: t, Msg: m[2], Raw: line, guessLevel: true}
+		e.guess(e.Msg)
+		return e, true
+	}
+	return Entry{}, false
+}
+
+// guess raises the level of an entry without one to error if text looks like a failure.
+func (e *Entry) guess(text string) {
+	if e.guessLevel && errorWords.MatchString(text) {
+		e.Level = slog.LevelError
+	}
+}
+
+func parseJSON(line string) (Entry, bool) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &fields); err != nil {
+		return Entry{}, false
+	}
+	e := Entry{Raw: line}
+	for key, value := range fields {
+		s, ok := value.(string)
+		if !ok {
+			b, _ := json.Marshal(value)
+			s = string(b)
+		}
+		e.set(key, s)
+	}
+	return e, true
+}
+
+// parseText reads the slog text format, key=value pairs where values with spaces are quoted.
+func parseText(line string) (Entry, bool) {
+	e := Entry{Raw: line}
+	rest := line
+	for rest != "" {
+		eq := strings.IndexByte(rest, '=')
+		if eq <= 0 {
+			return Entry{}, false
+		}
+		key := rest[:eq]
+		rest = rest[eq+1:]
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			quoted, err := strconv.QuotedPrefix(rest)
+			if err != nil {
+				return Entry{}, false
+			}
+			value, _ = strconv.Unquote(quoted)
+			rest = rest[len(quoted):]
+		} else if i := strings.IndexByte(rest, ' '); i >= 0 {
+			value, rest = rest[:i], rest[i:]
+		} else {
+			value, rest = rest, ""
+		}
+		e.set(key, value)
+		rest = strings.TrimLeft(rest, " ")
+	}
+	return e, true
+}
+
+func (e *Entry) set(key, value string) {
+	switch key {
+	case slog.TimeKey:
+		if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			e.Time = t
+			return
+		}
+	case slog.LevelKey:
+		if err := e.Level.UnmarshalText([]byte(value)); err == nil {
+			return
+		}
+	case slog.MessageKey:
+		e.Msg = value
+		return
+	}
+	if e.Fields == nil {
+		e.Fields = map[string]string{}
+	}
+	e.Fields[key] = value
+}
+
+// Reader reads entries from a log, joining continuation lines to their entry.
+type Reader struct {
+	scanner *bufio.Scanner
+	line    int
+	next    *Entry // the entry that ended the previous one
+	err     error
+}
+
+func NewReader(r io.Reader) *Reader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	return &Reader{scanner: scanner}
+}
+
+// Next returns the next entry, or io.EOF at the end.
+func (r *Reader) Next() (Entry, error) {
+	var current *Entry
+	if r.next != nil {
+		current, r.next = r.next, nil
+	}
+	for r.scanner.Scan() {
+		r.line++
+		text := r.scanner.Text()
+		e, ok := Parse(text)
+		if ok {
+			e.Line = r.line
+			if current != nil {
+				r.next = &e
+				return *current, nil
+			}
+			current = &e
+			continue
+		}
+		if current == nil {
+			if strings.TrimSpace(text) == "" {
+				continue
+			}
+			// the file starts in the middle of an entry
+			current = &Entry{Line: r.line, Msg: text, Raw: text}
+			continue
+		}
+		current.Msg += "\n" + text
+		current.Raw += "\n" + text
+		current.guess(text)
+	}
+	if err := r.scanner.Err(); err != nil {
+		return Entry{}, fmt.Errorf("logq: line %d: %w", r.line+1, err)
+	}
+	if current != nil {
+		return *current, nil
+	}
+	return Entry{}, io.EOF
+}
@@ -1,18 +1,27 @@
 package main
 
 import (
-	"errors"
 	"fmt"
-	"log"
+	"io"
 	"os"
-	"time"
+	"syscall"
+
+	"error-handling/errs"
+	"logs/logger"
+	"logs/rotate"
 )
 
-var ErrorDivideBeZero = errors.New("Divide by zero")
+var (
+	ErrorDivideBeZero   = errs.New(errs.Invalid, "Divide by zero")
+	ErrorNegativeDivide = errs.New(errs.Invalid, "Divider below zero")
+)
 
 func Divide2(nominator int, divider int) (float32, error) {
-	if divider <= 0 {
-		return 0, ErrorDivideBeZero
+	if divider == 0 {
+		return 0, ErrorDivideBeZero.With("nominator", nominator)
+	}
+	if divider < 0 {
+		return 0, ErrorNegativeDivide.With("nominator", nominator, "divider", divider)
 	}
 	return float32(nominator) / float32(divider), nil
 }
@@ -25,19 +34,32 @@ func Divide(nominator int, divider int) float32 {
 }
 
 func main() {
-	f, err := os.OpenFile("testlogfile", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
-
+	// a new file every day or every MB, the last week of files is kept gzipped
+	f, err := rotate.Open("testlogfile", rotate.Options{MaxSize: 1 << 20, Daily: true, MaxBackups: 7, Compress: true})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Could not log to file:", err)
+		os.Exit(1)
+	}
 	defer f.Close()
-	log.SetOutput(f)
+	stop := f.ReopenOn(syscall.SIGHUP)
+	defer stop()
+
+	// log to the terminal and the file, set LOG_LEVEL=debug to see more
+	logs := logger.New(logger.Options{Sinks: []io.Writer{os.Stderr, f}})
+	if err := logs.Configure(os.Getenv("LOG_LEVEL")); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	log := logs.For("batch")
 
-	log.Println("starting batch job", time.Now())
-	fmt.Println(Divide(10, 2))
+	log.Info("starting batch job")
+	log.Info("divided", "nominator", 10, "divider", 2, "result", Divide(10, 2))
 	val, err := Divide2(10, 0)
 	if err != nil {
-		log.Fatalln(err)
-		// fmt.Println(err)
-		// os.Exit(-1)
+		log.Error("batch job failed", "err", err)
+		f.Close()
+		os.Exit(1)
 	}
-	fmt.Println(val)
-	log.Println("stopping batch job", time.Now())
+	log.Info("divided", "nominator", 10, "divider", 0, "result", val)
+	log.Info("stopping batch job")
 }
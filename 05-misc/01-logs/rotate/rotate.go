@@ -0,0 +1,305 @@
+// Package rotate provides a file writer that rotates its file by size and
+// by day, compresses the old files and removes them after a while, so a
+// long running job can log without filling up the disk.
+//
+//	w, err := rotate.Open("logfile", rotate.Options{MaxSize: 10 << 20, Daily: true, MaxBackups: 7, Compress: true})
+//	defer w.Close()
+//	stop := w.ReopenOn(syscall.SIGHUP)
+//	defer stop()
+package rotate
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backups are named like logfile-2022-03-28T14-11-24.000.log
+const timeFormat = "2006-01-02T15-04-05.000"
+
+type Options struct {
+	MaxSize    int64         // rotate before the file grows beyond this many bytes, 0 means no limit
+	Daily      bool          // rotate when the first write of a new day comes in
+	MaxBackups int           // old files to keep, 0 keeps all
+	MaxAge     time.Duration // remove old files older than this, 0 keeps them
+	Compress   bool          // gzip old files
+}
+
+// Writer is an io.Writer writing to a file that it rotates. It's safe to
+// use from several goroutines, every Write ends up in one file.
+type Writer struct {
+	path string
+	opts Options
+	now  func() time.Time
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	opened time.Time
+
+	// compressing and removing happens in the background, one run at a time
+	mill sync.Mutex
+	wg   sync.WaitGroup
+}
+
+// Open opens or creates the file at path and appends to it.
+func Open(path string, opts Options) (*Writer, error) {
+	return open(path, opts, time.Now)
+}
+
+func open(path string, opts Options, now func() time.Time) (*Writer, error) {
+	w := &Writer{path: path, opts: opts, now: now}
+	if err := w.openFile(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *Writer) openFile() error {
+	f, err := os.OpenFile(w.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	w.file, w.size = f, info.Size()
+	w.opened = w.now()
+	if info.Size() > 0 {
+		// an existing file belongs to the day it was last written
+		w.opened = info.ModTime()
+	}
+	return nil
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		if err := w.openFile(); err != nil {
+			return 0, err
+		}
+	}
+	if w.due(int64(len(p))) {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// due reports whether writing n more bytes needs a new file. A single write
+// larger than MaxSize still goes to one file.
+func (w *Writer) due(n int64) bool {
+	if w.size == 0 {
+		return false
+	}
+	if w.opts.MaxSize > 0 && w.size+n > w.opts.MaxSize {
+		return true
+	}
+	if w.opts.Daily {
+		y1, m1, d1 := w.opened.Date()
+		y2, m2, d2 := w.now().Date()
+		return y1 != y2 || m1 != m2 || d1 != d2
+	}
+	return false
+}
+
+// Rotate moves the current file aside and starts a new one.
+func (w *Writer) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.rotate()
+}
+
+func (w *Writer) rotate() error {
+	if w.file != nil {
+		if err := w.file.Close(); err != nil {
+			return err
+		}
+		w.file = nil
+	}
+	if err := os.Rename(w.path, w.backupName(w.now())); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := w.openFile(); err != nil {
+		return err
+	}
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		w.mill.Lock()
+		defer w.mill.Unlock()
+		w.compress()
+		w.cleanup()
+	}()
+	return nil
+}
+
+// Reopen closes the file and opens path again, for when another program,
+// like logrotate, moved the file away.
+func (w *Writer) Reopen() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file != nil {
+		w.file.Close()
+		w.file = nil
+	}
+	return w.openFile()
+}
+
+// ReopenOn calls Reopen whenever one of sigs arrives, usually syscall.SIGHUP.
+// Call stop to stop listening.
+func (w *Writer) ReopenOn(sigs ...os.Signal) (stop func()) {
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, sigs...)
+	go func() {
+		for {
+			select {
+			case <-ch:
+				w.Reopen()
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
+
+// Close closes the file and waits for old files to be compressed and removed.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	var err error
+	if w.file != nil {
+		err = w.file.Close()
+		w.file = nil
+	}
+	w.mu.Unlock()
+	w.wg.Wait()
+	return err
+}
+
+func (w *Writer) split() (prefix string, ext string) {
+	ext = filepath.Ext(w.path)
+	return strings.TrimSuffix(w.path, ext) + "-", ext
+}
+
+// backupName names the backup made at t, moving t on if rotations
+// come quicker than once a millisecond.
+func (w *Writer) backupName(t time.Time) string {
+	prefix, ext := w.split()
+	for {
+		name := prefix + t.Format(timeFormat) + ext
+		if !exists(name) && !exists(name+".gz") {
+			return name
+		}
+		t = t.Add(time.Millisecond)
+	}
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+type backup struct {
+	path string
+	time time.Time
+}
+
+// backups lists the old files, newest first.
+func (w *Writer) backups() ([]backup, error) {
+	prefix, ext := w.split()
+	matches, err := filepath.Glob(prefix + "*")
+	if err != nil {
+		return nil, err
+	}
+	var list []backup
+	for _, path := range matches {
+		stamp := strings.TrimPrefix(path, prefix)
+		stamp = strings.TrimSuffix(strings.TrimSuffix(stamp, ".gz"), ext)
+		t, err := time.ParseInLocation(timeFormat, stamp, time.Local)
+		if err != nil {
+			continue
+		}
+		list = append(list, backup{path: path, time: t})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].time.After(list[j].time) })
+	return list, nil
+}
+
+func (w *Writer) compress() {
+	if !w.opts.Compress {
+		return
+	}
+	list, _ := w.backups()
+	for _, b := range list {
+		if !strings.HasSuffix(b.path, ".gz") {
+			gzipFile(b.path)
+		}
+	}
+}
+
+func (w *Writer) cleanup() {
+	if w.opts.MaxBackups <= 0 && w.opts.MaxAge <= 0 {
+		return
+	}
+	list, _ := w.backups()
+	cutoff := w.now().Add(-w.opts.MaxAge)
+	for i, b := range list {
+		tooMany := w.opts.MaxBackups > 0 && i >= w.opts.MaxBackups
+		tooOld := w.opts.MaxAge > 0 && b.time.Before(cutoff)
+		if tooMany || tooOld {
+			os.Remove(b.path)
+		}
+	}
+}
+
+// gzipFile replaces path with path.gz.
+func gzipFile(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(path+".gz", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(out)
+	if _, err := io.Copy(zw, in); err != nil {
+		out.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		out.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	in.Close()
+	return os.Remove(path)
+}
@@ -0,0 +1,200 @@
+package rotate
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+type clock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *clock) now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *clock) add(d time.Duration) {
+	c.mu.Lock()
+	c.t = c.t.Add(d)
+	c.mu.Unlock()
+}
+
+func openTest(t *testing.T, opts Options) (*Writer, *clock, string) {
+	t.Helper()
+	dir := t.TempDir()
+	c := &clock{t: time.Date(2022, 3, 28, 14, 11, 24, 0, time.Local)}
+	w, err := open(filepath.Join(dir, "batch.log"), opts, c.now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w, c, dir
+}
+
+func names(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var list []string
+	for _, e := range entries {
+		list = append(list, e.Name())
+	}
+	sort.Strings(list)
+	return list
+}
+
+func TestRotateBySize(t *testing.T) {
+	w, c, dir := openTest(t, Options{MaxSize: 10})
+	for i := 0; i < 3; i++ {
+		fmt.Fprintf(w, "line %d\n", i)
+		c.add(time.Second)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"batch-2022-03-28T14-11-25.000.log", "batch-2022-03-28T14-11-26.000.log", "batch.log"}
+	if actual := names(t, dir); strings.Join(actual, " ") != strings.Join(expected, " ") {
+		t.Errorf("Files, Actual: %v, Expected: %v", actual, expected)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "batch.log"))
+	if string(data) != "line 2\n" {
+		t.Errorf("Current file, Actual: %q, Expected: %q", data, "line 2\n")
+	}
+}
+
+func TestRotateDaily(t *testing.T) {
+	w, c, dir := openTest(t, Options{Daily: true})
+	io.WriteString(w, "monday\n")
+	c.add(2 * time.Hour)
+	io.WriteString(w, "still monday\n")
+	c.add(24 * time.Hour)
+	io.WriteString(w, "tuesday\n")
+	w.Close()
+
+	if actual := len(names(t, dir)); actual != 2 {
+		t.Errorf("File count, Actual: %d, Expected: %d", actual, 2)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "batch.log"))
+	if string(data) != "tuesday\n" {
+		t.Errorf("Current file, Actual: %q, Expected: %q", data, "tuesday\n")
+	}
+}
+
+func TestCompressAndKeepBackups(t *testing.T) {
+	w, c, dir := openTest(t, Options{MaxBackups: 2, Compress: true})
+	for i := 0; i < 5; i++ {
+		fmt.Fprintf(w, "file %d\n", i)
+		c.add(time.Minute)
+		w.Rotate()
+	}
+	w.Close()
+
+	files := names(t, dir)
+	expected := []string{"batch-2022-03-28T14-15-24.000.log.gz", "batch-2022-03-28T14-16-24.000.log.gz", "batch.log"}
+	if strings.Join(files, " ") != strings.Join(expected, " ") {
+		t.Fatalf("Files, Actual: %v, Expected: %v", files, expected)
+	}
+
+	f, _ := os.Open(filepath.Join(dir, files[1]))
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(zr)
+	if string(data) != "file 4\n" {
+		t.Errorf("Compressed content, Actual: %q, Expected: %q", data, "file 4\n")
+	}
+}
+
+func TestMaxAge(t *testing.T) {
+	w, c, dir := openTest(t, Options{MaxAge: 48 * time.Hour})
+	for i := 0; i < 4; i++ {
+		io.WriteString(w, "day\n")
+		c.add(24 * time.Hour)
+		w.Rotate()
+	}
+	w.Close()
+
+	// backups from 1, 2, 3 and 4 days after the start, only the first is older than two days
+	if actual := len(names(t, dir)); actual != 4 {
+		t.Errorf("File count, Actual: %d (%v), Expected: %d", actual, names(t, dir), 4)
+	}
+}
+
+func TestConcurrentWrites(t *testing.T) {
+	w, _, dir := openTest(t, Options{MaxSize: 256})
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				fmt.Fprintf(w, "goroutine %d line %d\n", g, i)
+			}
+		}(g)
+	}
+	wg.Wait()
+	w.Close()
+
+	lines := 0
+	for _, name := range names(t, dir) {
+		f, _ := os.Open(filepath.Join(dir, name))
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			if !strings.HasPrefix(scanner.Text(), "goroutine ") {
+				t.Errorf("Broken line in %s: %q", name, scanner.Text())
+			}
+			lines++
+		}
+		f.Close()
+	}
+	if lines != 800 {
+		t.Errorf("Line count, Actual: %d, Expected: %d", lines, 800)
+	}
+}
+
+func TestReopenOnSignal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no SIGHUP on windows")
+	}
+	w, _, dir := openTest(t, Options{})
+	defer w.Close()
+	stop := w.ReopenOn(syscall.SIGHUP)
+	defer stop()
+
+	io.WriteString(w, "before\n")
+	// what logrotate does, and then it sends SIGHUP
+	os.Rename(filepath.Join(dir, "batch.log"), filepath.Join(dir, "moved.log"))
+	syscall.Kill(os.Getpid(), syscall.SIGHUP)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !exists(filepath.Join(dir, "batch.log")) {
+		if time.Now().After(deadline) {
+			t.Fatal("file wasn't reopened")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	io.WriteString(w, "after\n")
+
+	data, _ := os.ReadFile(filepath.Join(dir, "batch.log"))
+	if string(data) != "after\n" {
+		t.Errorf("Reopened file, Actual: %q, Expected: %q", data, "after\n")
+	}
+}
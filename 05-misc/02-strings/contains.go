@@ -1,3 +1,6 @@
+//go:build ignore
+
+// Run with: go run contains.go
 package main
 
 import (
@@ -0,0 +1,3 @@
+module strings-example
+
+go 1.21
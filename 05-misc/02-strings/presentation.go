@@ -1,20 +1,51 @@
+//go:build ignore
+
+// Run with: go run presentation.go [-format terminal|markdown|html] [-template templates/person.tmpl]
 package main
 
 import (
+	"flag"
 	"fmt"
-	"strings"
+	"os"
+	"time"
+
+	"strings-example/render"
 )
 
 type Person struct {
 	Name    string
 	Address string
 	City    string
+	Since   time.Time `render:"Customer since"`
 }
 
 func main() {
-	person := Person{Name: "Jean Normand", Address: "123 Way", City: "Washington"}
+	format := flag.String("format", "terminal", "table format: terminal, markdown or html")
+	file := flag.String("template", "", "render with this template file instead of a table")
+	flag.Parse()
+
+	people := []Person{
+		{Name: "Jean Normand", Address: "123 Way", City: "Washington", Since: time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{Name: "Åsa Lindqvist", Address: "Storgatan 4", City: "Malmö", Since: time.Date(2021, 11, 15, 0, 0, 0, 0, time.UTC)},
+	}
+
+	if err := present(people, *format, *file); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
 
-	fmt.Println(strings.ToLower(person.Name))
-	fmt.Println(person.Address)
-	fmt.Println(strings.ToUpper(person.City))
+func present(people []Person, format, file string) error {
+	if file != "" {
+		t, err := render.ParseFile(file)
+		if err != nil {
+			return err
+		}
+		return t.Execute(os.Stdout, people)
+	}
+	f, err := render.ParseFormat(format)
+	if err != nil {
+		return err
+	}
+	return render.Table(os.Stdout, f, people)
 }
@@ -0,0 +1,67 @@
+// Package render presents data the same way everywhere: with your own
+// templates, loaded from files, or as a table for the terminal, Markdown
+// or HTML.
+package render
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"strings-example/textutil"
+)
+
+// DateLayout is how dates are shown when no layout is given.
+const DateLayout = "2006-01-02"
+
+// Funcs are the functions every template can use:
+//
+//	{{.Name | upper}}, {{.Name | lower}}, {{.Name | title}}
+//	{{.Name | pad 20}}, {{.Cost | padLeft 8}}, {{.Name | truncate 10}}
+//	{{.Since | date "Jan 2, 2006"}}
+var Funcs = map[string]any{
+	"upper":    strings.ToUpper,
+	"lower":    strings.ToLower,
+	"title":    textutil.Title,
+	"pad":      Pad,
+	"padLeft":  PadLeft,
+	"truncate": func(max int, s string) string { return textutil.Truncate(s, max, "…") },
+	"date":     Date,
+}
+
+// Pad fills s with spaces up to width columns. Wide characters count as two.
+func Pad(width int, v any) string {
+	s := fmt.Sprint(v)
+	if w := textutil.Width(s); w < width {
+		s += strings.Repeat(" ", width-w)
+	}
+	return s
+}
+
+// PadLeft is Pad with the spaces in front, for numbers.
+func PadLeft(width int, v any) string {
+	s := fmt.Sprint(v)
+	if w := textutil.Width(s); w < width {
+		s = strings.Repeat(" ", width-w) + s
+	}
+	return s
+}
+
+// Date formats a time.Time with layout. A zero time gives an empty string.
+func Date(layout string, v any) (string, error) {
+	var t time.Time
+	switch v := v.(type) {
+	case time.Time:
+		t = v
+	case *time.Time:
+		if v != nil {
+			t = *v
+		}
+	default:
+		return "", fmt.Errorf("date: %T is not a time", v)
+	}
+	if t.IsZero() {
+		return "", nil
+	}
+	return t.Format(layout), nil
+}
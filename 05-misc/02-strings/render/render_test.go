@@ -0,0 +1,146 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type person struct {
+	Name   string
+	City   string
+	Since  time.Time `render:"Since"`
+	secret string
+	Note   string `render:"-"`
+}
+
+var people = []person{
+	{Name: "Jean", City: "Washington", Since: time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC)},
+	{Name: "Åsa <b>", City: "日本|x"},
+}
+
+func TestTable(t *testing.T) {
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{Terminal, "" +
+			"Name     City        Since\n" +
+			"-------  ----------  ----------\n" +
+			"Jean     Washington  2019-03-01\n" +
+			"Åsa <b>  日本|x\n"},
+		{Markdown, "" +
+			"| Name | City | Since |\n" +
+			"|---|---|---|\n" +
+			"| Jean | Washington | 2019-03-01 |\n" +
+			"| Åsa <b> | 日本\\|x |  |\n"},
+	}
+	for _, tt := range tests {
+		var sb strings.Builder
+		if err := Table(&sb, tt.format, people); err != nil {
+			t.Fatal(err)
+		}
+		if got := sb.String(); got != tt.want {
+			t.Errorf("Table(%v) was incorrect, Actual:\n%s\nExpected:\n%s", tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestTableHTMLEscapes(t *testing.T) {
+	var sb strings.Builder
+	if err := Table(&sb, HTML, people); err != nil {
+		t.Fatal(err)
+	}
+	got := sb.String()
+	if !strings.Contains(got, "<td>Åsa &lt;b&gt;</td>") || !strings.Contains(got, "<th>Since</th>") {
+		t.Errorf("Table(HTML) was incorrect, Actual:\n%s", got)
+	}
+}
+
+func TestTableRejectsNonStructs(t *testing.T) {
+	if err := Table(&strings.Builder{}, Terminal, []int{1}); err == nil {
+		t.Errorf("Table([]int) was incorrect, Actual: nil, Expected: error")
+	}
+}
+
+func TestFuncs(t *testing.T) {
+	tests := []struct {
+		src  string
+		data any
+		want string
+	}{
+		{`{{. | upper}}`, "été", "ÉTÉ"},
+		{`{{. | lower}}`, "ÅSA", "åsa"},
+		{`{{. | title}}`, "the lord of the rings", "The Lord of the Rings"},
+		{`[{{. | pad 5}}]`, "日本", "[日本 ]"},
+		{`[{{. | padLeft 5}}]`, 42, "[   42]"},
+		{`{{. | truncate 4}}`, "Crème Brûlée", "Crè…"},
+		{`{{. | date "Jan 2006"}}`, time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC), "Mar 2019"},
+		{`[{{. | date "2006"}}]`, time.Time{}, "[]"},
+	}
+	for _, tt := range tests {
+		tmpl, err := Parse("test", tt.src, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var sb strings.Builder
+		if err := tmpl.Execute(&sb, tt.data); err != nil {
+			t.Fatal(err)
+		}
+		if got := sb.String(); got != tt.want {
+			t.Errorf("%s was incorrect, Actual: %q, Expected: %q", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestDateRejectsOtherTypes(t *testing.T) {
+	tmpl, _ := Parse("test", `{{. | date "2006"}}`, false)
+	if err := tmpl.Execute(&strings.Builder{}, "2019"); err == nil {
+		t.Errorf("date on a string was incorrect, Actual: nil, Expected: error")
+	}
+}
+
+func TestParseFile(t *testing.T) {
+	dir := t.TempDir()
+	for name, src := range map[string]string{
+		"p.tmpl": `<b>{{.Name | upper}}</b>`,
+		"p.html": `<b>{{.Name | upper}}</b>`,
+	} {
+		os.WriteFile(filepath.Join(dir, name), []byte(src), 0644)
+	}
+
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"p.tmpl", "<b>ÅSA <B></b>"},
+		{"p.html", "<b>ÅSA &lt;B&gt;</b>"},
+	}
+	for _, tt := range tests {
+		tmpl, err := ParseFile(filepath.Join(dir, tt.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var sb strings.Builder
+		if err := tmpl.Execute(&sb, people[1]); err != nil {
+			t.Fatal(err)
+		}
+		if got := sb.String(); got != tt.want {
+			t.Errorf("ParseFile(%s) was incorrect, Actual: %q, Expected: %q", tt.file, got, tt.want)
+		}
+	}
+	if _, err := ParseFile(filepath.Join(dir, "missing.tmpl")); err == nil {
+		t.Errorf("ParseFile(missing) was incorrect, Actual: nil, Expected: error")
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if f, err := ParseFormat("Markdown"); err != nil || f != Markdown {
+		t.Errorf("ParseFormat was incorrect, Actual: %v %v, Expected: markdown", f, err)
+	}
+	if _, err := ParseFormat("pdf"); err == nil {
+		t.Errorf("ParseFormat(pdf) was incorrect, Actual: nil, Expected: error")
+	}
+}
@@ -0,0 +1,184 @@
+package render
+
+import (
+	"fmt"
+	htemplate "html/template"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"strings-example/textutil"
+)
+
+// Format is where a table is shown.
+type Format int
+
+const (
+	Terminal Format = iota
+	Markdown
+	HTML
+)
+
+var formatNames = []string{"terminal", "markdown", "html"}
+
+func (f Format) String() string {
+	if f < 0 || int(f) >= len(formatNames) {
+		return fmt.Sprintf("Format(%d)", int(f))
+	}
+	return formatNames[f]
+}
+
+// ParseFormat returns the Format called name, like "markdown".
+func ParseFormat(name string) (Format, error) {
+	for i, n := range formatNames {
+		if strings.EqualFold(name, n) {
+			return Format(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown format %q, use one of %s", name, strings.Join(formatNames, ", "))
+}
+
+// Table writes rows, a slice of structs, as a table in format. Every
+// exported field is a column. Name the column with a tag, `render:"Customer since"`,
+// or leave the field out with `render:"-"`.
+func Table(w io.Writer, format Format, rows any) error {
+	header, cells, err := Columns(rows)
+	if err != nil {
+		return err
+	}
+	switch format {
+	case Terminal:
+		return terminalTable(w, header, cells)
+	case Markdown:
+		return markdownTable(w, header, cells)
+	case HTML:
+		return htmlTable.Execute(w, struct {
+			Header []string
+			Rows   [][]string
+		}{header, cells})
+	}
+	return fmt.Errorf("unknown format %v", format)
+}
+
+// Columns returns the column names and the cells, as text, of rows.
+func Columns(rows any) (header []string, cells [][]string, err error) {
+	v := reflect.ValueOf(rows)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, nil, fmt.Errorf("render: rows must be a slice of structs, not %T", rows)
+	}
+	typ := v.Type().Elem()
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("render: rows must be a slice of structs, not %T", rows)
+	}
+
+	var fields []int
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		name := f.Tag.Get("render")
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		header = append(header, name)
+		fields = append(fields, i)
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		row := reflect.Indirect(v.Index(i))
+		line := make([]string, len(fields))
+		if row.IsValid() {
+			for j, f := range fields {
+				line[j] = cell(row.Field(f).Interface())
+			}
+		}
+		cells = append(cells, line)
+	}
+	return header, cells, nil
+}
+
+func cell(v any) string {
+	switch v := v.(type) {
+	case time.Time:
+		s, _ := Date(DateLayout, v)
+		return s
+	case float32, float64:
+		return fmt.Sprintf("%.2f", v)
+	}
+	return fmt.Sprint(v)
+}
+
+func terminalTable(w io.Writer, header []string, cells [][]string) error {
+	widths := make([]int, len(header))
+	for i, h := range header {
+		widths[i] = textutil.Width(h)
+	}
+	for _, row := range cells {
+		for i, c := range row {
+			widths[i] = max(widths[i], textutil.Width(c))
+		}
+	}
+
+	line := func(row []string) string {
+		parts := make([]string, len(row))
+		for i, c := range row {
+			parts[i] = Pad(widths[i], c)
+		}
+		return strings.TrimRight(strings.Join(parts, "  "), " ") + "\n"
+	}
+	rules := make([]string, len(widths))
+	for i, width := range widths {
+		rules[i] = strings.Repeat("-", width)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(line(header))
+	sb.WriteString(line(rules))
+	for _, row := range cells {
+		sb.WriteString(line(row))
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>")
+
+func markdownTable(w io.Writer, header []string, cells [][]string) error {
+	line := func(row []string) string {
+		escaped := make([]string, len(row))
+		for i, c := range row {
+			escaped[i] = markdownEscaper.Replace(c)
+		}
+		return "| " + strings.Join(escaped, " | ") + " |\n"
+	}
+	rules := make([]string, len(header))
+	for i := range rules {
+		rules[i] = "---"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(line(header))
+	sb.WriteString("|" + strings.Join(rules, "|") + "|\n")
+	for _, row := range cells {
+		sb.WriteString(line(row))
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+var htmlTable = htemplate.Must(htemplate.New("table").Parse(`<table>
+  <thead>
+    <tr>{{range .Header}}<th>{{.}}</th>{{end}}</tr>
+  </thead>
+  <tbody>
+{{- range .Rows}}
+    <tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{- end}}
+  </tbody>
+</table>
+`))
@@ -0,0 +1,66 @@
+package render
+
+import (
+	htemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	ttemplate "text/template"
+)
+
+// Template is a text or an HTML template with Funcs available. HTML
+// templates escape the data, so a name like "<b>Jean</b>" can't break
+// the page.
+type Template struct {
+	text *ttemplate.Template
+	html *htemplate.Template
+}
+
+// Parse parses src as a text template, or as an HTML template if html is true.
+func Parse(name, src string, html bool) (*Template, error) {
+	if html {
+		t, err := htemplate.New(name).Funcs(Funcs).Parse(src)
+		if err != nil {
+			return nil, err
+		}
+		return &Template{html: t}, nil
+	}
+	t, err := ttemplate.New(name).Funcs(Funcs).Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	return &Template{text: t}, nil
+}
+
+// ParseFile reads a template from path. Files ending in .html, .htm or
+// .gohtml are HTML templates, anything else is text.
+func ParseFile(path string) (*Template, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(filepath.Base(path), string(src), IsHTMLFile(path))
+}
+
+// IsHTMLFile reports whether ParseFile treats path as an HTML template.
+func IsHTMLFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".html", ".htm", ".gohtml":
+		return true
+	}
+	return false
+}
+
+// IsHTML reports whether t is an HTML template.
+func (t *Template) IsHTML() bool {
+	return t.html != nil
+}
+
+// Execute writes data rendered with t to w.
+func (t *Template) Execute(w io.Writer, data any) error {
+	if t.html != nil {
+		return t.html.Execute(w, data)
+	}
+	return t.text.Execute(w, data)
+}
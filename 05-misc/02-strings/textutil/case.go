@@ -0,0 +1,83 @@
+package textutil
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Words splits an identifier or a sentence into words. It splits on
+// anything that isn't a letter or digit and where the case changes, so
+// "parseHTTPRequest", "parse_http_request" and "Parse HTTP request" all
+// give "parse", "HTTP", "request".
+func Words(s string) []string {
+	var words []string
+	runes := []rune(s)
+	start := -1
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				words = append(words, string(runes[start:i]))
+				start = -1
+			}
+			continue
+		}
+		if start >= 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			// "parseHTTP" splits before H, "HTTPRequest" before R
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || unicode.IsUpper(prev) && nextLower {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+// SnakeCase returns s as snake_case.
+func SnakeCase(s string) string {
+	return joinLower(Words(s), "_")
+}
+
+// KebabCase returns s as kebab-case.
+func KebabCase(s string) string {
+	return joinLower(Words(s), "-")
+}
+
+// CamelCase returns s as camelCase.
+func CamelCase(s string) string {
+	words := Words(s)
+	for i, w := range words {
+		if i == 0 {
+			words[i] = strings.ToLower(w)
+		} else {
+			words[i] = upperFirst(strings.ToLower(w))
+		}
+	}
+	return strings.Join(words, "")
+}
+
+// PascalCase returns s as PascalCase, the way exported Go names are written.
+func PascalCase(s string) string {
+	words := Words(s)
+	for i, w := range words {
+		words[i] = upperFirst(strings.ToLower(w))
+	}
+	return strings.Join(words, "")
+}
+
+func joinLower(words []string, sep string) string {
+	return strings.ToLower(strings.Join(words, sep))
+}
+
+func upperFirst(s string) string {
+	r, n := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[n:]
+}
@@ -0,0 +1,134 @@
+package textutil
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Levenshtein returns the number of characters you need to insert, delete
+// or replace to turn a into b. It counts runes, not bytes.
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// TypoDistance is Levenshtein, except that swapping two characters next
+// to each other, "lsit" for "list", counts as one edit instead of two.
+func TypoDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+// FuzzyMatch reports whether the characters of pattern appear in s in the
+// same order, ignoring case, like "fb" in "FooBar". The score is higher
+// when the matches are next to each other or start words.
+func FuzzyMatch(pattern, s string) (score int, ok bool) {
+	p := []rune(strings.ToLower(pattern))
+	if len(p) == 0 {
+		return 0, true
+	}
+	i := 0
+	prevMatch := false
+	var prev rune
+	for pos, r := range []rune(s) {
+		if i < len(p) && unicode.ToLower(r) == p[i] {
+			score++
+			if prevMatch {
+				score += 2
+			}
+			if pos == 0 || !unicode.IsLetter(prev) || unicode.IsLower(prev) && unicode.IsUpper(r) {
+				score += 3
+			}
+			i++
+			prevMatch = true
+		} else {
+			prevMatch = false
+		}
+		prev = r
+	}
+	if i < len(p) {
+		return 0, false
+	}
+	return score, true
+}
+
+// Match is a candidate found by Fuzzy.
+type Match struct {
+	Text  string
+	Score int
+}
+
+// Fuzzy returns the candidates that pattern fuzzy matches, best first.
+func Fuzzy(pattern string, candidates []string) []Match {
+	var matches []Match
+	for _, c := range candidates {
+		if score, ok := FuzzyMatch(pattern, c); ok {
+			matches = append(matches, Match{c, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	return matches
+}
+
+// Closest returns the candidates at most maxDistance edits away from
+// word, closest first, counting edits with TypoDistance. Use it for "did you mean" suggestions.
+func Closest(word string, candidates []string, maxDistance int) []string {
+	type candidate struct {
+		text     string
+		distance int
+	}
+	var found []candidate
+	lower := strings.ToLower(word)
+	for _, c := range candidates {
+		if d := TypoDistance(lower, strings.ToLower(c)); d <= maxDistance {
+			found = append(found, candidate{c, d})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].distance < found[j].distance
+	})
+	list := make([]string, len(found))
+	for i, c := range found {
+		list[i] = c.text
+	}
+	return list
+}
@@ -0,0 +1,109 @@
+package textutil
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+const zwj = '‍' // zero width joiner, glues emoji like 👩‍💻 together
+
+// Graphemes splits s into what a reader sees as single characters: a letter
+// with its combining accents, an emoji with its modifiers and joined
+// emoji, or a flag made of two regional indicators. It covers the common
+// cases of Unicode's rules (UAX #29), not all of them.
+func Graphemes(s string) []string {
+	var list []string
+	for len(s) > 0 {
+		n := nextGrapheme(s)
+		list = append(list, s[:n])
+		s = s[n:]
+	}
+	return list
+}
+
+// GraphemeCount returns the number of graphemes in s.
+func GraphemeCount(s string) int {
+	count := 0
+	for len(s) > 0 {
+		s = s[nextGrapheme(s):]
+		count++
+	}
+	return count
+}
+
+// nextGrapheme returns the length in bytes of the grapheme s starts with.
+func nextGrapheme(s string) int {
+	r, n := utf8.DecodeRuneInString(s)
+	if r == '\r' && len(s) > 1 && s[1] == '\n' {
+		return 2
+	}
+	if isRegionalIndicator(r) {
+		if r2, n2 := utf8.DecodeRuneInString(s[n:]); isRegionalIndicator(r2) {
+			return n + n2
+		}
+		return n
+	}
+	for n < len(s) {
+		r, size := utf8.DecodeRuneInString(s[n:])
+		switch {
+		case extends(r):
+			n += size
+		case r == zwj:
+			n += size
+			// the joiner takes the next character along
+			if n < len(s) {
+				_, size = utf8.DecodeRuneInString(s[n:])
+				n += size
+			}
+		default:
+			return n
+		}
+	}
+	return n
+}
+
+// extends reports whether r belongs to the character before it.
+func extends(r rune) bool {
+	return unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r) || unicode.Is(unicode.Mc, r) ||
+		r >= 0xfe00 && r <= 0xfe0f || // variation selectors
+		r >= 0x1f3fb && r <= 0x1f3ff // skin tones
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+// Width returns how many columns s takes in a terminal: wide characters,
+// like Chinese, Japanese and Korean characters and most emoji, take two.
+func Width(s string) int {
+	width := 0
+	for _, g := range Graphemes(s) {
+		width += graphemeWidth(g)
+	}
+	return width
+}
+
+func graphemeWidth(g string) int {
+	r, _ := utf8.DecodeRuneInString(g)
+	switch {
+	case r == '\t':
+		return 1
+	case unicode.IsControl(r):
+		return 0
+	case isWide(r):
+		return 2
+	}
+	return 1
+}
+
+func isWide(r rune) bool {
+	return r >= 0x1100 && r <= 0x115f || // Hangul Jamo
+		r >= 0x2e80 && r <= 0xa4cf && r != 0x303f || // CJK, Kana, Yi
+		r >= 0xac00 && r <= 0xd7a3 || // Hangul syllables
+		r >= 0xf900 && r <= 0xfaff || // CJK compatibility
+		r >= 0xfe30 && r <= 0xfe4f ||
+		r >= 0xff00 && r <= 0xff60 || r >= 0xffe0 && r <= 0xffe6 || // full width forms
+		r >= 0x1f300 && r <= 0x1f64f || r >= 0x1f900 && r <= 0x1f9ff || // emoji
+		isRegionalIndicator(r) ||
+		r >= 0x20000 && r <= 0x3fffd
+}
@@ -0,0 +1,56 @@
+package textutil
+
+import (
+	"strings"
+	"unicode"
+)
+
+// transliterations spells letters that don't split into a letter plus an
+// accent the way they're usually written in plain ASCII.
+var transliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'đ': "d", 'ð': "d", 'þ': "th", 'ł': "l", 'ı': "i",
+}
+
+// accented maps precomposed Latin letters to the letter without its accent.
+var accented = map[rune]rune{}
+
+func init() {
+	for base, letters := range map[rune]string{
+		'a': "àáâãäåāăą", 'c': "çćĉċč", 'd': "ď", 'e': "èéêëēĕėęě", 'g': "ĝğġģ", 'h': "ĥħ",
+		'i': "ìíîïĩīĭį", 'j': "ĵ", 'k': "ķ", 'l': "ĺļľŀ", 'n': "ñńņňŉ", 'o': "òóôõöōŏő",
+		'r': "ŕŗř", 's': "śŝşšș", 't': "ţťŧț", 'u': "ùúûüũūŭůűų", 'w': "ŵ", 'y': "ýÿŷ", 'z': "źżž",
+	} {
+		for _, r := range letters {
+			accented[r] = base
+		}
+	}
+}
+
+// Slugify turns s into something that can go in a URL: lower case, words
+// joined by hyphens, accents removed. "Crème Brûlée!" becomes "creme-brulee".
+// Letters from other scripts are kept, "Привет мир" becomes "привет-мир".
+func Slugify(s string) string {
+	var sb strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// a combining accent, drop it
+		case transliterations[r] != "":
+			sb.WriteString(transliterations[r])
+			hyphen = false
+		case accented[r] != 0:
+			sb.WriteRune(accented[r])
+			hyphen = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			sb.WriteRune(r)
+			hyphen = false
+		case r == '\'' || r == '’':
+			// "don't" becomes "dont"
+		case !hyphen && sb.Len() > 0:
+			sb.WriteByte('-')
+			hyphen = true
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "-")
+}
@@ -0,0 +1,144 @@
+// Package textutil has string functions that get Unicode right: title
+// casing, slugs, word wrapping, truncating, case conversion between
+// snake_case, camelCase and kebab-case, and fuzzy matching.
+package textutil
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SmallWords stay in lower case in a title, unless they come first or last.
+var SmallWords = []string{"a", "an", "and", "as", "at", "but", "by", "for", "in", "nor", "of", "on", "or", "the", "to", "with"}
+
+// Title capitalizes the words of s, keeping SmallWords in lower case.
+// "romeo and juliet" becomes "Romeo and Juliet".
+func Title(s string) string {
+	return TitleWith(s, SmallWords)
+}
+
+// TitleWith is Title with your own list of exceptions. Words already in
+// upper case, like NASA, stay that way.
+func TitleWith(s string, exceptions []string) string {
+	small := map[string]bool{}
+	for _, w := range exceptions {
+		small[strings.ToLower(w)] = true
+	}
+
+	words := strings.Fields(s)
+	for i, word := range words {
+		lower := strings.ToLower(word)
+		switch {
+		case isUpper(word) && utf8.RuneCountInString(word) > 1:
+			// an acronym
+		case small[lower] && i > 0 && i < len(words)-1:
+			words[i] = lower
+		default:
+			words[i] = capitalize(lower)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// capitalize upper cases the first letter of s and of every part after a hyphen.
+func capitalize(s string) string {
+	var sb strings.Builder
+	start := true
+	for _, r := range s {
+		if start && unicode.IsLetter(r) {
+			sb.WriteRune(unicode.ToTitle(r))
+			start = false
+			continue
+		}
+		if r == '-' {
+			start = true
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+func isUpper(s string) bool {
+	letters := 0
+	for _, r := range s {
+		if unicode.IsLetter(r) {
+			if !unicode.IsUpper(r) {
+				return false
+			}
+			letters++
+		}
+	}
+	return letters > 0
+}
+
+// Truncate shortens s to at most max graphemes, ending it with ellipsis
+// when something was cut off. It never splits an accented letter or an emoji.
+// A max of 0 or less gives "".
+func Truncate(s string, max int, ellipsis string) string {
+	if max <= 0 {
+		return ""
+	}
+	graphemes := Graphemes(s)
+	if len(graphemes) <= max {
+		return s
+	}
+	keep := max - GraphemeCount(ellipsis)
+	if keep <= 0 {
+		return strings.Join(Graphemes(ellipsis)[:max], "")
+	}
+	return strings.TrimRightFunc(strings.Join(graphemes[:keep], ""), unicode.IsSpace) + ellipsis
+}
+
+// Wrap breaks s into lines of at most width columns, breaking at spaces.
+// Words wider than width are split. Existing line breaks are kept.
+func Wrap(s string, width int) string {
+	if width < 1 {
+		return s
+	}
+	var lines []string
+	for _, paragraph := range strings.Split(s, "\n") {
+		lines = append(lines, wrapParagraph(paragraph, width)...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func wrapParagraph(s string, width int) []string {
+	var lines []string
+	var line strings.Builder
+	lineWidth := 0
+	for _, word := range strings.Fields(s) {
+		w := Width(word)
+		if lineWidth > 0 && lineWidth+1+w <= width {
+			line.WriteString(" " + word)
+			lineWidth += 1 + w
+			continue
+		}
+		if lineWidth > 0 {
+			lines = append(lines, line.String())
+			line.Reset()
+			lineWidth = 0
+		}
+		// split words that don't fit on a line of their own
+		for w > width {
+			part, partWidth := "", 0
+			for _, g := range Graphemes(word) {
+				gw := graphemeWidth(g)
+				if partWidth+gw > width && partWidth > 0 {
+					break
+				}
+				part += g
+				partWidth += gw
+			}
+			lines = append(lines, part)
+			word = word[len(part):]
+			w = Width(word)
+		}
+		line.WriteString(word)
+		lineWidth = w
+	}
+	if lineWidth > 0 || len(lines) == 0 {
+		lines = append(lines, line.String())
+	}
+	return lines
+}
@@ -0,0 +1,248 @@
+package textutil
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTitle(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"romeo and juliet", "Romeo and Juliet"},
+		{"the lord of the rings", "The Lord of the Rings"},
+		{"what to look for", "What to Look For"},
+		{"a NASA mission", "A NASA Mission"},
+		{"über die brücke", "Über Die Brücke"},
+		{"  élan   vital ", "Élan Vital"},
+		{"ǆungla", "ǅungla"},
+		{"jean-luc picard", "Jean-Luc Picard"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Title(tt.in); got != tt.want {
+			t.Errorf("Title(%q) was incorrect, Actual: %q, Expected: %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTitleWith(t *testing.T) {
+	got := TitleWith("die brücke am fluss", []string{"die", "am"})
+	if want := "Die Brücke am Fluss"; got != want {
+		t.Errorf("TitleWith was incorrect, Actual: %q, Expected: %q", got, want)
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Hello, World!", "hello-world"},
+		{"Crème Brûlée", "creme-brulee"},
+		{"Straße in Łódź", "strasse-in-lodz"},
+		{"Crème", "creme"},
+		{"Привет мир", "привет-мир"},
+		{"東京 2020", "東京-2020"},
+		{"don't stop", "dont-stop"},
+		{"--already--slugged--", "already-slugged"},
+		{"!!!", ""},
+	}
+	for _, tt := range tests {
+		if got := Slugify(tt.in); got != tt.want {
+			t.Errorf("Slugify(%q) was incorrect, Actual: %q, Expected: %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestGraphemes(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"abc", []string{"a", "b", "c"}},
+		{"été", []string{"é", "t", "é"}},
+		{"👍🏽!", []string{"👍🏽", "!"}},
+		{"👩‍💻x", []string{"👩‍💻", "x"}},
+		{"🇸🇪🇳🇴", []string{"🇸🇪", "🇳🇴"}},
+		{"a\r\nb", []string{"a", "\r\n", "b"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := Graphemes(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Graphemes(%q) was incorrect, Actual: %q, Expected: %q", tt.in, got, tt.want)
+		}
+		if got := GraphemeCount(tt.in); got != len(tt.want) {
+			t.Errorf("GraphemeCount(%q) was incorrect, Actual: %d, Expected: %d", tt.in, got, len(tt.want))
+		}
+	}
+}
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"hello", 5},
+		{"héllo", 5},
+		{"héllo", 5},
+		{"日本語", 6},
+		{"👍🏽", 2},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if got := Width(tt.in); got != tt.want {
+			t.Errorf("Width(%q) was incorrect, Actual: %d, Expected: %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		in       string
+		max      int
+		ellipsis string
+		want     string
+	}{
+		{"hello world", 20, "…", "hello world"},
+		{"hello world", 8, "…", "hello w…"},
+		{"hello world", 7, "…", "hello…"},
+		{"hello world", 8, "...", "hello..."},
+		{"éééé", 3, "…", "éé…"},
+		{"👍🏽👍🏽👍🏽", 2, "…", "👍🏽…"},
+		{"🇸🇪🇳🇴🇩🇰", 2, "…", "🇸🇪…"},
+		{"hello", 2, "...", ".."},
+		{"hello", 0, "…", ""},
+		{"hello", -1, "…", ""},
+		{"", -1, "…", ""},
+	}
+	for _, tt := range tests {
+		if got := Truncate(tt.in, tt.max, tt.ellipsis); got != tt.want {
+			t.Errorf("Truncate(%q, %d) was incorrect, Actual: %q, Expected: %q", tt.in, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		in    string
+		width int
+		want  string
+	}{
+		{"the quick brown fox", 10, "the quick\nbrown fox"},
+		{"the quick brown fox", 100, "the quick brown fox"},
+		{"één twee drie", 8, "één twee\ndrie"},
+		{"日本語 の テキスト", 8, "日本語\nの\nテキスト"},
+		{"abcdefghij", 4, "abcd\nefgh\nij"},
+		{"first\n\nsecond line", 6, "first\n\nsecond\nline"},
+		{"", 5, ""},
+	}
+	for _, tt := range tests {
+		if got := Wrap(tt.in, tt.width); got != tt.want {
+			t.Errorf("Wrap(%q, %d) was incorrect, Actual: %q, Expected: %q", tt.in, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestCase(t *testing.T) {
+	tests := []struct {
+		in                          string
+		snake, kebab, camel, pascal string
+	}{
+		{"parseHTTPRequest", "parse_http_request", "parse-http-request", "parseHttpRequest", "ParseHttpRequest"},
+		{"parse_http_request", "parse_http_request", "parse-http-request", "parseHttpRequest", "ParseHttpRequest"},
+		{"Hello World", "hello_world", "hello-world", "helloWorld", "HelloWorld"},
+		{"userID2", "user_id2", "user-id2", "userId2", "UserId2"},
+		{"größeÄnderung", "größe_änderung", "größe-änderung", "größeÄnderung", "GrößeÄnderung"},
+		{"ΚαλήΜέρα", "καλή_μέρα", "καλή-μέρα", "καλήΜέρα", "ΚαλήΜέρα"},
+		{"", "", "", "", ""},
+	}
+	for _, tt := range tests {
+		if got := SnakeCase(tt.in); got != tt.snake {
+			t.Errorf("SnakeCase(%q) was incorrect, Actual: %q, Expected: %q", tt.in, got, tt.snake)
+		}
+		if got := KebabCase(tt.in); got != tt.kebab {
+			t.Errorf("KebabCase(%q) was incorrect, Actual: %q, Expected: %q", tt.in, got, tt.kebab)
+		}
+		if got := CamelCase(tt.in); got != tt.camel {
+			t.Errorf("CamelCase(%q) was incorrect, Actual: %q, Expected: %q", tt.in, got, tt.camel)
+		}
+		if got := PascalCase(tt.in); got != tt.pascal {
+			t.Errorf("PascalCase(%q) was incorrect, Actual: %q, Expected: %q", tt.in, got, tt.pascal)
+		}
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"kitten", "sitting", 3},
+		{"", "abc", 3},
+		{"abc", "abc", 0},
+		{"café", "cafe", 1},
+		{"naïve", "naive", 1},
+		{"日本", "日本語", 1},
+	}
+	for _, tt := range tests {
+		if got := Levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("Levenshtein(%q, %q) was incorrect, Actual: %d, Expected: %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestTypoDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"lsit", "list", 1},
+		{"lsit", "quit", 2},
+		{"kitten", "sitting", 3},
+		{"ca", "abc", 3},
+		{"Zürihc", "Zürich", 1},
+	}
+	for _, tt := range tests {
+		if got := TypoDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("TypoDistance(%q, %q) was incorrect, Actual: %d, Expected: %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		pattern, s string
+		ok         bool
+	}{
+		{"fb", "FooBar", true},
+		{"bf", "FooBar", false},
+		{"", "anything", true},
+		{"äpf", "Äpfel", true},
+		{"zz", "Zürich", false},
+	}
+	for _, tt := range tests {
+		if _, ok := FuzzyMatch(tt.pattern, tt.s); ok != tt.ok {
+			t.Errorf("FuzzyMatch(%q, %q) was incorrect, Actual: %v, Expected: %v", tt.pattern, tt.s, ok, tt.ok)
+		}
+	}
+}
+
+func TestFuzzy(t *testing.T) {
+	got := Fuzzy("lst", []string{"delete", "list", "last seen", "blast"})
+	var texts []string
+	for _, m := range got {
+		texts = append(texts, m.Text)
+	}
+	want := []string{"list", "last seen", "blast"}
+	if !reflect.DeepEqual(texts, want) {
+		t.Errorf("Fuzzy was incorrect, Actual: %v, Expected: %v", texts, want)
+	}
+}
+
+func TestClosest(t *testing.T) {
+	got := Closest("lis", []string{"list", "add", "lost", "quit"}, 2)
+	want := []string{"list", "lost"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Closest was incorrect, Actual: %v, Expected: %v", got, want)
+	}
+}
@@ -0,0 +1,158 @@
+// Package books is a catalog of books, read from and written to XML, JSON
+// and CSV, with queries and per author statistics.
+package books
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"json-example/money"
+)
+
+type Book struct {
+	Author string      `xml:"author" json:"author"`
+	Title  string      `xml:"title" json:"title"`
+	Pages  int         `xml:"pages" json:"pages"`
+	Type   string      `xml:"type" json:"type"`
+	Cost   money.Money `xml:"cost" json:"cost"`
+}
+
+// Types lists the types a book can have.
+var Types = []string{"paperback", "hardcover", "ebook", "audiobook"}
+
+// Books is a list of books, its methods filter it and return a new list,
+// so queries can be chained: books.ByAuthor("Shakespeare").OfType("paperback").
+type Books []Book
+
+// ValidationError is a problem with one field of one book.
+type ValidationError struct {
+	Index int // of the book in the list, from 0
+	Title string
+	Field string
+	Msg   string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("book %d (%q): %s %s", e.Index+1, e.Title, e.Field, e.Msg)
+}
+
+// Validate checks every book and returns all problems found, joined with errors.Join.
+func (b Books) Validate() error {
+	var errs []error
+	for i, book := range b {
+		add := func(field, msg string) {
+			errs = append(errs, &ValidationError{Index: i, Title: book.Title, Field: field, Msg: msg})
+		}
+		if strings.TrimSpace(book.Author) == "" {
+			add("author", "is missing")
+		}
+		if strings.TrimSpace(book.Title) == "" {
+			add("title", "is missing")
+		}
+		if book.Pages <= 0 {
+			add("pages", fmt.Sprintf("must be above 0, not %d", book.Pages))
+		}
+		if !validType(book.Type) {
+			add("type", fmt.Sprintf("must be one of %s, not %q", strings.Join(Types, ", "), book.Type))
+		}
+		if book.Cost.Amount < 0 {
+			add("cost", fmt.Sprintf("can't be negative, not %v", book.Cost))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func validType(t string) bool {
+	for _, valid := range Types {
+		if t == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// Where returns the books keep returns true for.
+func (b Books) Where(keep func(Book) bool) Books {
+	result := Books{}
+	for _, book := range b {
+		if keep(book) {
+			result = append(result, book)
+		}
+	}
+	return result
+}
+
+// ByAuthor returns the books of author, ignoring case.
+func (b Books) ByAuthor(author string) Books {
+	return b.Where(func(book Book) bool { return strings.EqualFold(book.Author, author) })
+}
+
+// OfType returns the books of type t, like paperback.
+func (b Books) OfType(t string) Books {
+	return b.Where(func(book Book) bool { return strings.EqualFold(book.Type, t) })
+}
+
+// PriceBetween returns the books costing at least min and at most max. A max of 0 means no upper limit.
+// Books in another currency than min and max are left out.
+func (b Books) PriceBetween(min, max money.Money) Books {
+	return b.Where(func(book Book) bool {
+		if c, err := book.Cost.Cmp(min); err != nil || c < 0 {
+			return false
+		}
+		if max.IsZero() {
+			return true
+		}
+		c, err := book.Cost.Cmp(max)
+		return err == nil && c <= 0
+	})
+}
+
+type AuthorStats struct {
+	Author      string      `json:"author"`
+	Books       int         `json:"books"`
+	Pages       int         `json:"pages"`
+	TotalCost   money.Money `json:"total_cost"`
+	AverageCost money.Money `json:"average_cost"`
+	MinCost     money.Money `json:"min_cost"`
+	MaxCost     money.Money `json:"max_cost"`
+}
+
+// StatsByAuthor sums up the books of each author, sorted by author.
+// The books of an author must share a currency, money.ErrCurrencyMismatch otherwise.
+func (b Books) StatsByAuthor() ([]AuthorStats, error) {
+	byAuthor := map[string]*AuthorStats{}
+	for _, book := range b {
+		s, ok := byAuthor[book.Author]
+		if !ok {
+			s = &AuthorStats{Author: book.Author, TotalCost: money.New(0, book.Cost.Currency), MinCost: book.Cost, MaxCost: book.Cost}
+			byAuthor[book.Author] = s
+		}
+		s.Books++
+		s.Pages += book.Pages
+		total, err := s.TotalCost.Add(book.Cost)
+		if err != nil {
+			return nil, fmt.Errorf("books: %s: %w", book.Author, err)
+		}
+		s.TotalCost = total
+		if c, _ := book.Cost.Cmp(s.MinCost); c < 0 {
+			s.MinCost = book.Cost
+		}
+		if c, _ := book.Cost.Cmp(s.MaxCost); c > 0 {
+			s.MaxCost = book.Cost
+		}
+	}
+
+	stats := make([]AuthorStats, 0, len(byAuthor))
+	for _, s := range byAuthor {
+		average, err := s.TotalCost.Div(int64(s.Books))
+		if err != nil {
+			return nil, err
+		}
+		s.AverageCost = average
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Author < stats[j].Author })
+	return stats, nil
+}
@@ -0,0 +1,131 @@
+package books
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"json-example/money"
+)
+
+func load(t *testing.T) Books {
+	t.Helper()
+	f, err := os.Open("../books.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	b, err := ReadXML(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestReadXML(t *testing.T) {
+	b := load(t)
+	expected := Books{
+		{Author: "Shakespeare", Title: "Romeo and Juliet", Pages: 400, Type: "paperback", Cost: money.MustParse("17")},
+		{Author: "Shakespeare", Title: "Hamlet", Pages: 270, Type: "paperback", Cost: money.MustParse("15")},
+	}
+	if !reflect.DeepEqual(b, expected) {
+		t.Errorf("ReadXML was incorrect, Actual: %+v, Expected: %+v", b, expected)
+	}
+	if err := b.Validate(); err != nil {
+		t.Errorf("books.xml should be valid, Actual: %v", err)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	b := load(t)
+	for _, format := range []string{"xml", "json"} {
+		var buf bytes.Buffer
+		if err := Write(&buf, b, format); err != nil {
+			t.Fatal(err)
+		}
+		back, err := Read(&buf, format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(back, b) {
+			t.Errorf("%s round trip was incorrect, Actual: %+v, Expected: %+v", format, back, b)
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	WriteCSV(&buf, Books{{Author: "Shakespeare", Title: "Hamlet, Prince of Denmark", Pages: 270, Type: "paperback", Cost: money.MustParse("15.5")}})
+	expected := "author,title,pages,type,cost\nShakespeare,\"Hamlet, Prince of Denmark\",270,paperback,15.50\n"
+	if buf.String() != expected {
+		t.Errorf("WriteCSV was incorrect, Actual: %q, Expected: %q", buf.String(), expected)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	b := Books{
+		{Author: "Shakespeare", Title: "Hamlet", Pages: 270, Type: "paperback", Cost: money.MustParse("15")},
+		{Author: "", Title: "Untitled", Pages: 0, Type: "scroll", Cost: money.MustParse("-1")},
+	}
+	err := b.Validate()
+	var v *ValidationError
+	if !errors.As(err, &v) || v.Index != 1 {
+		t.Fatalf("Validate should return a *ValidationError, Actual: %v", err)
+	}
+	for _, field := range []string{"author", "pages", "type", "cost"} {
+		if !strings.Contains(err.Error(), `"Untitled"): `+field) {
+			t.Errorf("Validate should report %s, Actual: %v", field, err)
+		}
+	}
+}
+
+func TestQueries(t *testing.T) {
+	b := Books{
+		{Author: "Shakespeare", Title: "Hamlet", Pages: 270, Type: "paperback", Cost: money.MustParse("15")},
+		{Author: "Shakespeare", Title: "Macbeth", Pages: 200, Type: "hardcover", Cost: money.MustParse("25")},
+		{Author: "Austen", Title: "Emma", Pages: 500, Type: "paperback", Cost: money.MustParse("10")},
+	}
+	cases := []struct {
+		name     string
+		result   Books
+		expected []string
+	}{
+		{"by author", b.ByAuthor("shakespeare"), []string{"Hamlet", "Macbeth"}},
+		{"of type", b.OfType("paperback"), []string{"Hamlet", "Emma"}},
+		{"price", b.PriceBetween(money.MustParse("12"), money.MustParse("20")), []string{"Hamlet"}},
+		{"no max", b.PriceBetween(money.MustParse("12"), money.Money{}), []string{"Hamlet", "Macbeth"}},
+		{"other currency", b.PriceBetween(money.MustParse("12 USD"), money.Money{}), nil},
+		{"chained", b.ByAuthor("Shakespeare").OfType("hardcover"), []string{"Macbeth"}},
+		{"none", b.ByAuthor("Dickens"), nil},
+	}
+	for _, c := range cases {
+		var titles []string
+		for _, book := range c.result {
+			titles = append(titles, book.Title)
+		}
+		if !reflect.DeepEqual(titles, c.expected) {
+			t.Errorf("Query %s, Actual: %v, Expected: %v", c.name, titles, c.expected)
+		}
+	}
+
+	stats, err := b.StatsByAuthor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := money.MustParse
+	expected := []AuthorStats{
+		{Author: "Austen", Books: 1, Pages: 500, TotalCost: m("10"), AverageCost: m("10"), MinCost: m("10"), MaxCost: m("10")},
+		{Author: "Shakespeare", Books: 2, Pages: 470, TotalCost: m("40"), AverageCost: m("20"), MinCost: m("15"), MaxCost: m("25")},
+	}
+	if !reflect.DeepEqual(stats, expected) {
+		t.Errorf("StatsByAuthor was incorrect, Actual: %+v, Expected: %+v", stats, expected)
+	}
+
+	mixed := append(b, Book{Author: "Austen", Title: "Persuasion", Pages: 250, Type: "ebook", Cost: m("9 EUR")})
+	if _, err := mixed.StatsByAuthor(); !errors.Is(err, money.ErrCurrencyMismatch) {
+		t.Errorf("StatsByAuthor should fail with ErrCurrencyMismatch, Actual: %v", err)
+	}
+}
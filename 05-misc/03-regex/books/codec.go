@@ -0,0 +1,110 @@
+package books
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// document is the XML layout, <books><book>...</book></books>.
+type document struct {
+	XMLName xml.Name `xml:"books"`
+	Books   Books    `xml:"book"`
+}
+
+func ReadXML(r io.Reader) (Books, error) {
+	var doc document
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("books: %w", err)
+	}
+	if doc.Books == nil {
+		doc.Books = Books{}
+	}
+	return doc.Books, nil
+}
+
+func WriteXML(w io.Writer, b Books) error {
+	io.WriteString(w, xml.Header)
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(document{Books: b}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// ReadJSON reads a JSON array of books. Unknown fields are an error, to catch typos.
+func ReadJSON(r io.Reader) (Books, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	var b Books
+	if err := dec.Decode(&b); err != nil {
+		return nil, fmt.Errorf("books: %w", err)
+	}
+	return b, nil
+}
+
+func WriteJSON(w io.Writer, b Books) error {
+	if b == nil {
+		b = Books{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(b)
+}
+
+var csvHeader = []string{"author", "title", "pages", "type", "cost"}
+
+func WriteCSV(w io.Writer, b Books) error {
+	cw := csv.NewWriter(w)
+	cw.Write(csvHeader)
+	for _, book := range b {
+		cw.Write([]string{
+			book.Author,
+			book.Title,
+			strconv.Itoa(book.Pages),
+			book.Type,
+			book.Cost.String(),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Formats lists the formats Read and Write understand.
+var Formats = []string{"xml", "json", "csv"}
+
+// FormatOf guesses the format from a file name, like books.xml.
+func FormatOf(path string) string {
+	return strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+}
+
+// Read reads books in format, xml or json.
+func Read(r io.Reader, format string) (Books, error) {
+	switch format {
+	case "xml":
+		return ReadXML(r)
+	case "json":
+		return ReadJSON(r)
+	}
+	return nil, fmt.Errorf("books: can't read %q, use xml or json", format)
+}
+
+// Write writes books in format, xml, json or csv.
+func Write(w io.Writer, b Books, format string) error {
+	switch format {
+	case "xml":
+		return WriteXML(w, b)
+	case "json":
+		return WriteJSON(w, b)
+	case "csv":
+		return WriteCSV(w, b)
+	}
+	return fmt.Errorf("books: can't write %q, use one of %s", format, strings.Join(Formats, ", "))
+}
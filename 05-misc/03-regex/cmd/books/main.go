@@ -0,0 +1,121 @@
+// Command books imports a books catalog from XML and exports it as JSON,
+// CSV or XML, with filters, or shows statistics per author.
+//
+//	books import -o books.json books.xml
+//	books export -format csv -author Shakespeare -max 16 books.json
+//	books stats books.xml
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"json-example/money"
+	"regex/books"
+)
+
+const usage = `Usage: books <command> [flags] file
+
+Commands:
+  import    read an XML catalog, validate it and write it as JSON
+  export    write the catalog, or the books matching the filters, as json, csv or xml
+  stats     show the number of books, pages and costs per author
+
+The file is XML or JSON, told apart by its extension. Run books <command> -h for the flags.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	cmd := os.Args[1]
+	switch cmd {
+	case "import", "export", "stats":
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", cmd, usage)
+		os.Exit(2)
+	}
+	flags := flag.NewFlagSet(cmd, flag.ExitOnError)
+	var (
+		out              = flags.String("o", "", "write to this file instead of stdout")
+		format           = flags.String("format", "json", "export format: json, csv or xml")
+		author           = flags.String("author", "", "only books by this author")
+		kind             = flags.String("type", "", "only books of this type, like paperback")
+		minCost, maxCost money.Money
+	)
+	flags.TextVar(&minCost, "min", money.Money{}, "only books costing at least this much, like 10 or 10 USD")
+	flags.TextVar(&maxCost, "max", money.Money{}, "only books costing at most this much, 0 means no limit")
+	flags.Parse(os.Args[2:])
+	if flags.NArg() != 1 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	catalog, err := load(flags.Arg(0))
+	if err != nil {
+		fail(err)
+	}
+
+	if cmd == "import" {
+		if err := catalog.Validate(); err != nil {
+			fail(fmt.Errorf("the catalog has problems:\n%w", err))
+		}
+	}
+	var stats []books.AuthorStats
+	if cmd == "stats" {
+		if stats, err = catalog.StatsByAuthor(); err != nil {
+			fail(err)
+		}
+	}
+
+	w := os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fail(err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch cmd {
+	case "import":
+		err = books.WriteJSON(w, catalog)
+	case "export":
+		if *author != "" {
+			catalog = catalog.ByAuthor(*author)
+		}
+		if *kind != "" {
+			catalog = catalog.OfType(*kind)
+		}
+		catalog = catalog.PriceBetween(minCost, maxCost)
+		err = books.Write(w, catalog, *format)
+	case "stats":
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', tabwriter.AlignRight)
+		fmt.Fprintln(tw, "AUTHOR\tBOOKS\tPAGES\tTOTAL\tAVERAGE\tMIN\tMAX\t")
+		for _, s := range stats {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\t%s\t%s\t\n", s.Author, s.Books, s.Pages, s.TotalCost, s.AverageCost, s.MinCost, s.MaxCost)
+		}
+		err = tw.Flush()
+	}
+	if err != nil {
+		fail(err)
+	}
+}
+
+func load(path string) (books.Books, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return books.Read(f, books.FormatOf(path))
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "books:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,83 @@
+// Command retest is an interactive regular expression tester.
+//
+//	$ retest
+//	> :p (?P<protocol>\w+)://(?P<domain>[\w.]+)
+//	> http://myapi.com/products
+//
+// With -history, what you enter is kept in a file between runs:
+//
+//	$ retest -history ~/.retest_history
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"regex/retest"
+)
+
+// how many lines of history are kept between runs
+const historySize = 500
+
+func main() {
+	pattern := flag.String("p", "", "start with this pattern")
+	noColor := flag.Bool("no-color", false, "mark matches with [ ] instead of colors")
+	historyFile := flag.String("history", "", "load the history from this file and save it back on exit, like ~/.retest_history")
+	flag.Parse()
+
+	s := &retest.Session{Color: !*noColor && isTerminal(os.Stdout)}
+	s.LoadHistory(readHistory(*historyFile))
+	loaded := len(s.History())
+
+	if *pattern != "" {
+		s.Handle(os.Stdout, ":p "+*pattern)
+	}
+	interactive := isTerminal(os.Stdin)
+	if interactive {
+		fmt.Println("Type a pattern with :p, then inputs to test it on. :help shows the commands.")
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		if interactive {
+			fmt.Print("> ")
+		}
+		if !scanner.Scan() || !s.Handle(os.Stdout, scanner.Text()) {
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	writeHistory(*historyFile, s.History(), loaded)
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func readHistory(path string) []string {
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	return strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+}
+
+// writeHistory saves the history, if anything was added to it.
+func writeHistory(path string, history []string, loaded int) {
+	if path == "" || len(history) == loaded {
+		return
+	}
+	if len(history) > historySize {
+		history = history[len(history)-historySize:]
+	}
+	os.WriteFile(path, []byte(strings.Join(history, "\n")+"\n"), 0600)
+}
@@ -0,0 +1,94 @@
+// Command urlinfo shows the parts of URLs and their normal form.
+//
+//	urlinfo http://myapi.com/products?page=1
+//	cat urls.txt | urlinfo -json
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"regex/urlinfo"
+)
+
+func main() {
+	asJSON := flag.Bool("json", false, "print each URL as a JSON object")
+	normalized := flag.Bool("n", false, "only print the normalized URLs")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: urlinfo [flags] [url ...]\n\nReads one URL per line from stdin without arguments.\n\nFlags:")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	urls := flag.Args()
+	if len(urls) == 0 {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				urls = append(urls, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	}
+
+	failed := false
+	enc := json.NewEncoder(os.Stdout)
+	for _, raw := range urls {
+		info, err := urlinfo.Parse(raw)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			failed = true
+			continue
+		}
+		switch {
+		case *normalized:
+			fmt.Println(info.Normalized)
+		case *asJSON:
+			enc.Encode(info)
+		default:
+			printInfo(os.Stdout, info)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func printInfo(w io.Writer, info *urlinfo.Info) {
+	fmt.Fprintf(w, "scheme:     %s\n", info.Scheme)
+	if info.User != "" {
+		fmt.Fprintf(w, "user:       %s\n", info.User)
+	}
+	if info.Password != "" {
+		fmt.Fprintf(w, "password:   %s\n", strings.Repeat("*", len(info.Password)))
+	}
+	fmt.Fprintf(w, "host:       %s\n", info.Host)
+	if info.Port != 0 {
+		fmt.Fprintf(w, "port:       %d\n", info.Port)
+	}
+	fmt.Fprintf(w, "path:       %s\n", info.Path)
+	for i, s := range info.Segments {
+		fmt.Fprintf(w, "  [%d]      %s\n", i, s)
+	}
+	keys := make([]string, 0, len(info.Query))
+	for key := range info.Query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(w, "query:      %s = %s\n", key, strings.Join(info.Query[key], ", "))
+	}
+	if info.Fragment != "" {
+		fmt.Fprintf(w, "fragment:   %s\n", info.Fragment)
+	}
+	fmt.Fprintf(w, "normalized: %s\n\n", info.Normalized)
+}
@@ -0,0 +1,81 @@
+// Command xmltool renames XML elements and attributes, and converts
+// repeating elements to JSON or CSV.
+//
+//	xmltool rename -map books-mapping.json books.xml
+//	xmltool json -record book books.xml
+//	xmltool csv -record book books.xml > books.csv
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"regex/xmltransform"
+)
+
+const usage = `Usage: xmltool <command> [flags] [file]
+
+Commands:
+  rename -map mapping.json   rename elements and attributes
+  json -record name          write each <name> element as a JSON object
+  csv -record name           write each <name> element as a CSV row
+
+Reads stdin without a file and writes to stdout.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	cmd := os.Args[1]
+	flags := flag.NewFlagSet(cmd, flag.ExitOnError)
+	mapping := flags.String("map", "", "JSON file with the elements and attributes to rename")
+	record := flags.String("record", "", "name of the repeating element, like book")
+	flags.Parse(os.Args[2:])
+
+	in := io.Reader(os.Stdin)
+	if flags.NArg() > 0 {
+		f, err := os.Open(flags.Arg(0))
+		if err != nil {
+			fail(err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	var err error
+	switch cmd {
+	case "rename":
+		if *mapping == "" {
+			fail(fmt.Errorf("rename needs -map"))
+		}
+		var m xmltransform.Mapping
+		if m, err = xmltransform.LoadMapping(*mapping); err == nil {
+			err = xmltransform.Rename(os.Stdout, in, m)
+		}
+	case "json", "csv":
+		if *record == "" {
+			fail(fmt.Errorf("%s needs -record", cmd))
+		}
+		records := xmltransform.Records(in, *record)
+		if cmd == "json" {
+			err = xmltransform.WriteJSON(os.Stdout, records)
+		} else {
+			err = xmltransform.WriteCSV(os.Stdout, records)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", cmd, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "xmltool:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,44 @@
+//go:build ignore
+
+// Run with: go run extract.go
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"regex/extract"
+)
+
+type URL struct {
+	Protocol string `re:"protocol"`
+	Domain   string `re:"domain"`
+	Route    string `re:"route"`
+}
+
+type Item struct {
+	Id       int `re:"id"`
+	Quantity int `re:"quantity"`
+}
+
+func main() {
+	// the named groups go straight into the struct, no SubexpNames() loop needed
+	p := extract.MustCompile(`^(?P<protocol>\w+):\/\/(?P<domain>\w+\.\w+)\/(?P<route>\w+)\/?`)
+	url, err := extract.Find[URL](p, "http://myapi.com/products")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("%+v\n", url)
+
+	// the groups are converted to the field types, here int
+	records := "item,quantity\n112, 2\n94, 3\n"
+	items := extract.MustCompile(`(?P<id>\d+),\s*(?P<quantity>\d+)`)
+	for item, err := range extract.Scan[Item](items, strings.NewReader(records)) {
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("%+v\n", item)
+	}
+}
@@ -0,0 +1,73 @@
+package extract
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Cache keeps the most recently used patterns compiled, for code that
+// builds its expressions at run time but uses the same ones over and over.
+type Cache struct {
+	mu       sync.Mutex
+	size     int
+	patterns map[string]*list.Element
+	order    *list.List // most recently used at the front
+}
+
+type cacheEntry struct {
+	expr    string
+	pattern *Pattern
+}
+
+// NewCache creates a cache holding up to size patterns.
+func NewCache(size int) *Cache {
+	if size < 1 {
+		size = 1
+	}
+	return &Cache{size: size, patterns: map[string]*list.Element{}, order: list.New()}
+}
+
+// Get returns the compiled expr, compiling it only if it isn't in the cache.
+func (c *Cache) Get(expr string) (*Pattern, error) {
+	c.mu.Lock()
+	if e, ok := c.patterns[expr]; ok {
+		c.order.MoveToFront(e)
+		c.mu.Unlock()
+		return e.Value.(*cacheEntry).pattern, nil
+	}
+	c.mu.Unlock()
+
+	// compile without holding the lock, two goroutines may both compile expr
+	p, err := Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.patterns[expr]; ok {
+		c.order.MoveToFront(e)
+		return e.Value.(*cacheEntry).pattern, nil
+	}
+	c.patterns[expr] = c.order.PushFront(&cacheEntry{expr: expr, pattern: p})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.patterns, oldest.Value.(*cacheEntry).expr)
+	}
+	return p, nil
+}
+
+// Len returns the number of cached patterns.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+var defaultCache = NewCache(256)
+
+// Cached compiles expr through a cache shared by the whole program.
+func Cached(expr string) (*Pattern, error) {
+	return defaultCache.Get(expr)
+}
@@ -0,0 +1,213 @@
+package extract
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldError is a group that couldn't be converted to its field's type.
+type FieldError struct {
+	Field string
+	Group string
+	Value string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("extract: group %s into field %s: can't convert %q: %v", e.Group, e.Field, e.Value, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// plan says which group goes into which field of a struct type.
+type plan struct {
+	fields []fieldPlan
+}
+
+type fieldPlan struct {
+	index  []int
+	name   string
+	group  string
+	number int    // the group's number in the pattern
+	layout string // for time.Time, from the tag option layout=...
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	durationType  = reflect.TypeOf(time.Duration(0))
+	unmarshalType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// plan returns the cached plan for t, building it on first use. Fields are
+// matched to groups by their re tag, or else by their name ignoring case.
+// A tagged field without a group is an error, an untagged one is skipped.
+func (p *Pattern) plan(t reflect.Type) (*plan, error) {
+	if cached, ok := p.plans.Load(t); ok {
+		return cached.(*plan), nil
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("extract: can only fill structs, not %v", t)
+	}
+
+	groups := map[string]int{}
+	for i, name := range p.re.SubexpNames() {
+		if name != "" {
+			groups[name] = i
+		}
+	}
+
+	pl := &plan{}
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+		tag := field.Tag.Get("re")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		fp := fieldPlan{index: field.Index, name: field.Name, group: name}
+		if strings.HasPrefix(opts, "layout=") {
+			fp.layout = strings.TrimPrefix(opts, "layout=")
+		}
+
+		if name != "" {
+			n, ok := groups[name]
+			if !ok {
+				return nil, fmt.Errorf("extract: field %s wants group %q, which isn't in %s", field.Name, name, p.re)
+			}
+			fp.number = n
+		} else {
+			for group, n := range groups {
+				if strings.EqualFold(group, field.Name) {
+					fp.group, fp.number = group, n
+				}
+			}
+			if fp.number == 0 {
+				continue
+			}
+		}
+		if !convertible(field.Type) {
+			return nil, fmt.Errorf("extract: field %s has type %v, which can't be converted from text", field.Name, field.Type)
+		}
+		pl.fields = append(pl.fields, fp)
+	}
+
+	cached, _ := p.plans.LoadOrStore(t, pl)
+	return cached.(*plan), nil
+}
+
+func convertible(t reflect.Type) bool {
+	if reflect.PointerTo(t).Implements(unmarshalType) || t == timeType {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Ptr:
+		return convertible(t.Elem())
+	}
+	return false
+}
+
+// fill sets the fields of v from the match m, as returned by FindStringSubmatchIndex.
+func (pl *plan) fill(v reflect.Value, s string, m []int) error {
+	for _, fp := range pl.fields {
+		start, end := m[2*fp.number], m[2*fp.number+1]
+		if start < 0 {
+			// an optional group that didn't take part in the match leaves the zero value
+			continue
+		}
+		text := s[start:end]
+		if err := set(v.FieldByIndex(fp.index), text, fp.layout); err != nil {
+			return &FieldError{Field: fp.name, Group: fp.group, Value: text, Err: err}
+		}
+	}
+	return nil
+}
+
+func set(f reflect.Value, text string, layout string) error {
+	if f.Kind() == reflect.Ptr {
+		if text == "" {
+			return nil
+		}
+		p := reflect.New(f.Type().Elem())
+		if err := set(p.Elem(), text, layout); err != nil {
+			return err
+		}
+		f.Set(p)
+		return nil
+	}
+	if f.Type() == timeType {
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		t, err := time.Parse(layout, text)
+		if err != nil {
+			return err
+		}
+		f.Set(reflect.ValueOf(t))
+		return nil
+	}
+	if f.Type() == durationType {
+		d, err := time.ParseDuration(text)
+		if err != nil {
+			return err
+		}
+		f.SetInt(int64(d))
+		return nil
+	}
+	if u, ok := f.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(text))
+	}
+
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(text)
+	case reflect.Bool:
+		b, err := parseBool(text)
+		if err != nil {
+			return err
+		}
+		f.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(text, 10, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(text, 10, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(text, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetFloat(n)
+	}
+	return nil
+}
+
+// parseBool accepts what strconv.ParseBool does, and yes, no, on and off.
+func parseBool(text string) (bool, error) {
+	switch strings.ToLower(text) {
+	case "yes", "y", "on":
+		return true, nil
+	case "no", "n", "off":
+		return false, nil
+	}
+	return strconv.ParseBool(text)
+}
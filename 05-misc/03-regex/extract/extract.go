@@ -0,0 +1,149 @@
+// Package extract copies the named groups of a regular expression into
+// struct fields, converting them to the field's type on the way:
+//
+//	type URL struct {
+//		Protocol string `re:"protocol"`
+//		Domain   string `re:"domain"`
+//		Port     int    `re:"port"`
+//	}
+//
+//	p := extract.MustCompile(`^(?P<protocol>\w+)://(?P<domain>[\w.]+)(?::(?P<port>\d+))?`)
+//	u, err := extract.Find[URL](p, "http://myapi.com:8080/products")
+package extract
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+	"regexp"
+	"sync"
+)
+
+// ErrNoMatch is returned by Find when the input doesn't match.
+var ErrNoMatch = errors.New("extract: no match")
+
+// Pattern is a compiled regular expression that remembers how to fill each
+// struct type it has been used with. It's safe for concurrent use.
+type Pattern struct {
+	re    *regexp.Regexp
+	plans sync.Map // reflect.Type to *plan
+}
+
+func Compile(expr string) (*Pattern, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	return &Pattern{re: re}, nil
+}
+
+func MustCompile(expr string) *Pattern {
+	p, err := Compile(expr)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func (p *Pattern) Regexp() *regexp.Regexp {
+	return p.re
+}
+
+func (p *Pattern) String() string {
+	return p.re.String()
+}
+
+// Map returns the named groups of the first match, by name. It's false if s doesn't match.
+func (p *Pattern) Map(s string) (map[string]string, bool) {
+	m := p.re.FindStringSubmatch(s)
+	if m == nil {
+		return nil, false
+	}
+	result := make(map[string]string)
+	for i, name := range p.re.SubexpNames() {
+		if i != 0 && name != "" {
+			result[name] = m[i]
+		}
+	}
+	return result, true
+}
+
+// Into fills the struct dst points to from the first match in s.
+func (p *Pattern) Into(s string, dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("extract: Into needs a pointer to a struct, not %T", dst)
+	}
+	pl, err := p.plan(v.Elem().Type())
+	if err != nil {
+		return err
+	}
+	m := p.re.FindStringSubmatchIndex(s)
+	if m == nil {
+		return ErrNoMatch
+	}
+	return pl.fill(v.Elem(), s, m)
+}
+
+// Find returns a T filled from the first match in s.
+func Find[T any](p *Pattern, s string) (T, error) {
+	var t T
+	err := p.Into(s, &t)
+	return t, err
+}
+
+// All yields a T for every match in s, stopping after the first conversion error.
+func All[T any](p *Pattern, s string) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var t T
+		pl, err := p.plan(reflect.TypeOf(t))
+		if err != nil {
+			yield(t, err)
+			return
+		}
+		for _, m := range p.re.FindAllStringSubmatchIndex(s, -1) {
+			var t T
+			err := pl.fill(reflect.ValueOf(&t).Elem(), s, m)
+			if !yield(t, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Scan yields a T for every match in r, reading one line at a time, so it
+// works on inputs of any size. Matches can't span lines. It stops after
+// the first error.
+func Scan[T any](p *Pattern, r io.Reader) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		pl, err := p.plan(reflect.TypeOf(zero))
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+		line := 0
+		for scanner.Scan() {
+			line++
+			text := scanner.Text()
+			for _, m := range p.re.FindAllStringSubmatchIndex(text, -1) {
+				var t T
+				if err := pl.fill(reflect.ValueOf(&t).Elem(), text, m); err != nil {
+					yield(t, fmt.Errorf("line %d: %w", line, err))
+					return
+				}
+				if !yield(t, nil) {
+					return
+				}
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package extract
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type URL struct {
+	Protocol string `re:"protocol"`
+	Domain   string `re:"domain"`
+	Port     *int   `re:"port"`
+	Route    string // matched by name
+	Ignored  string `re:"-"`
+}
+
+var urlPattern = MustCompile(`^(?P<protocol>\w+)://(?P<domain>[\w.]+)(?::(?P<port>\d+))?/(?P<route>\w+)`)
+
+func TestFind(t *testing.T) {
+	u, err := Find[URL](urlPattern, "http://myapi.com:8080/products")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Protocol != "http" || u.Domain != "myapi.com" || u.Port == nil || *u.Port != 8080 || u.Route != "products" {
+		t.Errorf("Find was incorrect, Actual: %+v", u)
+	}
+
+	u, _ = Find[URL](urlPattern, "http://myapi.com/products")
+	if u.Port != nil {
+		t.Errorf("An optional group that didn't match should leave nil, Actual: %v", *u.Port)
+	}
+
+	if _, err := Find[URL](urlPattern, "not a url"); err != ErrNoMatch {
+		t.Errorf("Find, Actual: %v, Expected: %v", err, ErrNoMatch)
+	}
+}
+
+func TestMap(t *testing.T) {
+	m, ok := urlPattern.Map("http://myapi.com/products")
+	if !ok || m["protocol"] != "http" || m["domain"] != "myapi.com" || m["route"] != "products" || m["port"] != "" {
+		t.Errorf("Map was incorrect, Actual: %v", m)
+	}
+}
+
+type Entry struct {
+	When    time.Time     `re:"when,layout=2006/01/02 15:04:05"`
+	Took    time.Duration `re:"took"`
+	Ok      bool          `re:"ok"`
+	Size    uint16        `re:"size"`
+	Ratio   float64       `re:"ratio"`
+	Address netip.Addr    `re:"addr"` // a TextUnmarshaler
+}
+
+func TestConversions(t *testing.T) {
+	p := MustCompile(`(?P<when>\S+ \S+) took=(?P<took>\S+) ok=(?P<ok>\w+) size=(?P<size>\d+) ratio=(?P<ratio>[\d.]+) addr=(?P<addr>\S+)`)
+	e, err := Find[Entry](p, "2022/03/28 14:11:24 took=1.5s ok=yes size=512 ratio=0.25 addr=10.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Entry{
+		When:    time.Date(2022, 3, 28, 14, 11, 24, 0, time.UTC),
+		Took:    1500 * time.Millisecond,
+		Ok:      true,
+		Size:    512,
+		Ratio:   0.25,
+		Address: netip.MustParseAddr("10.0.0.1"),
+	}
+	if e != expected {
+		t.Errorf("Conversions were incorrect,\nActual:   %+v\nExpected: %+v", e, expected)
+	}
+
+	_, err = Find[Entry](p, "2022/03/28 14:11:24 took=1.5s ok=yes size=99999 ratio=0.25 addr=10.0.0.1")
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Field != "Size" || !errors.Is(err, strconv.ErrRange) {
+		t.Errorf("Out of range size, Actual: %v", err)
+	}
+}
+
+func TestBadStructs(t *testing.T) {
+	type missing struct {
+		Name string `re:"name"`
+	}
+	if _, err := Find[missing](urlPattern, "http://myapi.com/products"); err == nil || !strings.Contains(err.Error(), `"name"`) {
+		t.Errorf("A tag without a group should fail, Actual: %v", err)
+	}
+
+	type unsupported struct {
+		Route []string `re:"route"`
+	}
+	if _, err := Find[unsupported](urlPattern, "http://myapi.com/products"); err == nil {
+		t.Errorf("A field of an unsupported type should fail")
+	}
+}
+
+type Item struct {
+	Id       int `re:"id"`
+	Quantity int `re:"quantity"`
+}
+
+var itemPattern = MustCompile(`(?P<id>\d+),\s*(?P<quantity>\d+)`)
+
+func TestAll(t *testing.T) {
+	var items []Item
+	for item, err := range All[Item](itemPattern, "112, 2; 94, 3; 7,1") {
+		if err != nil {
+			t.Fatal(err)
+		}
+		items = append(items, item)
+	}
+	if fmt.Sprint(items) != "[{112 2} {94 3} {7 1}]" {
+		t.Errorf("All was incorrect, Actual: %v", items)
+	}
+}
+
+func TestScan(t *testing.T) {
+	var input strings.Builder
+	input.WriteString("item,quantity\n")
+	for i := 0; i < 10000; i++ {
+		fmt.Fprintf(&input, "%d, %d\n", i, i%5)
+	}
+
+	count, total := 0, 0
+	for item, err := range Scan[Item](itemPattern, strings.NewReader(input.String())) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		count++
+		total += item.Quantity
+		if count == 5000 {
+			break
+		}
+	}
+	if count != 5000 || total != 10000 {
+		t.Errorf("Scan was incorrect, Actual: %d items, %d total, Expected: 5000 items, 10000 total", count, total)
+	}
+
+	// the error says which line is wrong
+	bad := MustCompile(`(?P<id>\d+),\s*(?P<quantity>\S+)`)
+	for _, err := range Scan[Item](bad, strings.NewReader("1, 2\n3, x\n")) {
+		if err != nil && !strings.HasPrefix(err.Error(), "line 2:") {
+			t.Errorf("Scan error, Actual: %v", err)
+		}
+	}
+}
+
+func TestCache(t *testing.T) {
+	c := NewCache(2)
+	a1, _ := c.Get(`a+`)
+	c.Get(`b+`)
+	a2, _ := c.Get(`a+`) // a+ is now the most recently used
+	c.Get(`c+`)          // pushes out b+
+
+	if a1 != a2 {
+		t.Errorf("Get should return the cached pattern")
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len, Actual: %d, Expected: %d", c.Len(), 2)
+	}
+	if _, ok := c.patterns[`b+`]; ok {
+		t.Errorf("The least recently used pattern should be dropped")
+	}
+	if _, err := c.Get(`(`); err == nil {
+		t.Errorf("Get should fail on a bad expression")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			p, err := Cached(fmt.Sprintf(`x{%d}`, i%3))
+			if err != nil || !p.Regexp().MatchString("xxx") {
+				t.Errorf("Cached, Actual: %v %v", p, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func BenchmarkFind(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Find[URL](urlPattern, "http://myapi.com:8080/products")
+	}
+}
@@ -0,0 +1,13 @@
+module regex
+
+go 1.23
+
+require (
+	json-example v0.0.0
+	strings-example v0.0.0
+)
+
+replace (
+	json-example => ../../04-webdev/01-json
+	strings-example => ../../05-misc/02-strings
+)
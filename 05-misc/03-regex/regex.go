@@ -1,33 +1,26 @@
 package main
 
 import (
+	"bufio"
 	"fmt"
-	"log"
-	"regexp"
+	"os"
+	"strings"
+
+	"regex/urlinfo"
 )
 
 func main() {
-	var url string
 	fmt.Println("Type URL: ")
-	fmt.Scan(&url)
-	//
-	// "^([a-z]*\\.)+[a-z]*@
-	r, err := regexp.Compile(`^(?P<protocol>\w+):\/\/(?P<domain>\w+\.\w+)\/(?P<route>\w+)\/?`)
+	// read the whole line, fmt.Scan would stop at the first space
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+
+	info, err := urlinfo.Parse(strings.TrimSpace(line))
 	if err != nil {
-		log.Fatal("Error compiling: ", err)
-	}
-	m := r.FindStringSubmatch(url)
-	if m == nil {
-		panic("mo match")
-	}
-	result := make(map[string]string)
-	for i, name := range r.SubexpNames() {
-		if i != 0 && name != "" {
-			result[name] = m[i]
-		}
+		fmt.Println(err)
+		os.Exit(1)
 	}
 	fmt.Println("The URL consist of:")
-	fmt.Println(result["protocol"])
-	fmt.Println(result["domain"])
-	fmt.Println(result["route"])
+	fmt.Println("protocol:", info.Scheme)
+	fmt.Println("domain:", info.Host)
+	fmt.Println("route:", strings.Join(info.Segments, "/"))
 }
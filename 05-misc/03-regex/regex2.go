@@ -1,8 +1,14 @@
+//go:build ignore
+
+// Run with: go run regex2.go
 package main
 
 import (
 	"fmt"
-	"regexp"
+	"os"
+	"strings"
+
+	"regex/xmltransform"
 )
 
 func main() {
@@ -23,11 +29,10 @@ func main() {
     </book>
 </books>`
 
-	r := regexp.MustCompile(`(\<\/?)(title)(\>)`)
-	s := r.ReplaceAllString(file, "${1}name${3}")
-	fmt.Println(s)
-
-	r = regexp.MustCompile(`(\<\/?)(cost)(\>)`)
-	s = r.ReplaceAllString(s, "${1}price${3}")
-	fmt.Println(s)
+	// only element names change, a title inside the text or an attribute stays as it is
+	m := xmltransform.Mapping{Elements: map[string]string{"title": "name", "cost": "price"}}
+	if err := xmltransform.Rename(os.Stdout, strings.NewReader(file), m); err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println()
 }
@@ -0,0 +1,352 @@
+// Package retest is an interactive tester for regular expressions. Enter a
+// pattern, then sample inputs, and see what matches, what each group
+// captured and what a replacement would produce.
+package retest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+)
+
+const Help = `Commands:
+  :p <pattern>     set the pattern, like :p (?P<id>\d+),\s*(?P<quantity>\d+)
+  :r <template>    preview replacing matches with template, like :r ${quantity}x${id}
+  :r               stop previewing replacements
+  :f <flags>       set flags, any of i (ignore case), m (multi-line), s (. matches \n), U (ungreedy)
+  :history         list what you entered
+  :export          print the pattern as a Go snippet
+  :help            show this help
+  :quit            leave
+Anything else is an input to test the pattern on.
+`
+
+// Session holds the pattern under test and what was entered so far.
+type Session struct {
+	Color bool // highlight matches with terminal colors instead of [ ]
+
+	pattern  string
+	flags    string
+	re       *regexp.Regexp
+	template string
+	replace  bool
+	history  []string
+}
+
+// SetPattern compiles pattern with the current flags. On an error the
+// previous pattern stays, and the error is a *PatternError.
+func (s *Session) SetPattern(pattern string) error {
+	re, err := compile(pattern, s.flags)
+	if err != nil {
+		return err
+	}
+	s.pattern, s.re = pattern, re
+	return nil
+}
+
+// SetFlags changes the flags and recompiles the pattern.
+func (s *Session) SetFlags(flags string) error {
+	for _, f := range flags {
+		if !strings.ContainsRune("imsU", f) {
+			return fmt.Errorf("unknown flag %q, use i, m, s or U", f)
+		}
+	}
+	if s.pattern != "" {
+		re, err := compile(s.pattern, flags)
+		if err != nil {
+			return err
+		}
+		s.re = re
+	}
+	s.flags = flags
+	return nil
+}
+
+// SetReplace starts previewing replacements, an empty template stops it.
+func (s *Session) SetReplace(template string) {
+	s.template, s.replace = template, template != ""
+}
+
+func (s *Session) History() []string {
+	return s.history
+}
+
+// LoadHistory puts earlier entries in front of the history, so they show up in :history.
+func (s *Session) LoadHistory(lines []string) {
+	s.history = append(append([]string(nil), lines...), s.history...)
+}
+
+// PatternError is a pattern that doesn't compile, with where the problem is.
+type PatternError struct {
+	Pattern string
+	Pos     int // byte offset of the problem in Pattern, -1 if unknown
+	Err     *syntax.Error
+}
+
+func (e *PatternError) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Err.Code, e.Pos+1)
+}
+
+func (e *PatternError) Unwrap() error {
+	return e.Err
+}
+
+// Caret returns the pattern with a ^ under the problem.
+func (e *PatternError) Caret() string {
+	if e.Pos < 0 {
+		return e.Pattern
+	}
+	return e.Pattern + "\n" + strings.Repeat(" ", len([]rune(e.Pattern[:e.Pos]))) + "^"
+}
+
+func compile(pattern string, flags string) (*regexp.Regexp, error) {
+	expr := pattern
+	if flags != "" {
+		expr = "(?" + flags + ")" + pattern
+	}
+	re, err := regexp.Compile(expr)
+	var syntaxErr *syntax.Error
+	if errors.As(err, &syntaxErr) {
+		// flags never cause an error, compile the pattern alone so the error
+		// is about what the user typed, not the (?flags) prefix
+		if _, plainErr := regexp.Compile(pattern); plainErr != nil {
+			errors.As(plainErr, &syntaxErr)
+		}
+		return nil, &PatternError{Pattern: pattern, Pos: errorPos(pattern, syntaxErr), Err: syntaxErr}
+	}
+	return re, err
+}
+
+// errorPos finds where in pattern the problem of err is, -1 if it can't tell.
+func errorPos(pattern string, err *syntax.Error) int {
+	switch err.Code {
+	case syntax.ErrMissingParen, syntax.ErrUnexpectedParen:
+		// the error quotes the whole pattern, look for the paren itself
+		return unmatchedParen(pattern)
+	}
+	if err.Expr == "" {
+		return -1
+	}
+	// the error quotes the part of the pattern it's about
+	return strings.LastIndex(pattern, err.Expr)
+}
+
+// unmatchedParen returns the position of the first ) without a (, or else
+// of the last ( without a ), or -1 if the parens match. Escaped parens and
+// parens in character classes like [()] don't count.
+func unmatchedParen(pattern string) int {
+	var open []int
+	inClass := false
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '\\':
+			i++
+		case inClass:
+			inClass = c != ']'
+		case c == '[':
+			inClass = true
+			// a ] right after [ or [^ is a literal
+			if strings.HasPrefix(pattern[i+1:], "^") {
+				i++
+			}
+			if strings.HasPrefix(pattern[i+1:], "]") {
+				i++
+			}
+		case c == '(':
+			open = append(open, i)
+		case c == ')':
+			if len(open) == 0 {
+				return i
+			}
+			open = open[:len(open)-1]
+		}
+	}
+	if len(open) > 0 {
+		return open[len(open)-1]
+	}
+	return -1
+}
+
+// Handle runs one line entered by the user and writes the outcome to w.
+// It returns false when the user wants to quit.
+func (s *Session) Handle(w io.Writer, line string) bool {
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return true
+	}
+	if line != ":history" {
+		s.history = append(s.history, line)
+	}
+
+	cmd, arg, _ := strings.Cut(line, " ")
+	switch cmd {
+	case ":q", ":quit", ":exit":
+		return false
+	case ":h", ":help":
+		fmt.Fprint(w, Help)
+	case ":p", ":pattern":
+		if err := s.SetPattern(arg); err != nil {
+			writeError(w, err)
+			return true
+		}
+		fmt.Fprintf(w, "pattern: %s\n", s.re)
+		if names := groupNames(s.re); len(names) > 0 {
+			fmt.Fprintf(w, "groups:  %s\n", strings.Join(names, ", "))
+		}
+	case ":f", ":flags":
+		if err := s.SetFlags(arg); err != nil {
+			writeError(w, err)
+			return true
+		}
+		fmt.Fprintf(w, "flags: %q\n", s.flags)
+	case ":r", ":replace":
+		s.SetReplace(arg)
+		if s.replace {
+			fmt.Fprintf(w, "replace with: %s\n", arg)
+		} else {
+			fmt.Fprintln(w, "not replacing")
+		}
+	case ":history":
+		for i, h := range s.history {
+			fmt.Fprintf(w, "%4d  %s\n", i+1, h)
+		}
+	case ":export":
+		if s.re == nil {
+			fmt.Fprintln(w, "set a pattern first, with :p")
+			return true
+		}
+		fmt.Fprint(w, s.Snippet())
+	default:
+		if strings.HasPrefix(line, ":") && !strings.HasPrefix(line, "::") {
+			fmt.Fprintf(w, "unknown command %s, type :help for the commands, or start the line with :: to test it as input\n", cmd)
+			return true
+		}
+		s.test(w, strings.TrimPrefix(line, ":"))
+	}
+	return true
+}
+
+func writeError(w io.Writer, err error) {
+	var pe *PatternError
+	if errors.As(err, &pe) {
+		fmt.Fprintf(w, "error: %v\n%s\n", err, pe.Caret())
+		return
+	}
+	fmt.Fprintf(w, "error: %v\n", err)
+}
+
+func groupNames(re *regexp.Regexp) []string {
+	var names []string
+	for i, name := range re.SubexpNames() {
+		if i == 0 {
+			continue
+		}
+		if name == "" {
+			name = strconv.Itoa(i)
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// test shows the matches of the pattern in input.
+func (s *Session) test(w io.Writer, input string) {
+	if s.re == nil {
+		fmt.Fprintln(w, "set a pattern first, with :p")
+		return
+	}
+	matches := s.re.FindAllStringSubmatchIndex(input, -1)
+	if len(matches) == 0 {
+		fmt.Fprintln(w, "no match")
+		return
+	}
+
+	fmt.Fprintln(w, s.highlight(input, matches))
+	names := s.re.SubexpNames()
+	for n, m := range matches {
+		fmt.Fprintf(w, "match %d: %q at %d-%d\n", n+1, input[m[0]:m[1]], m[0], m[1])
+		for i := 1; i < len(names); i++ {
+			label := strconv.Itoa(i)
+			if names[i] != "" {
+				label += " " + names[i]
+			}
+			if m[2*i] < 0 {
+				fmt.Fprintf(w, "  %-12s (no match)\n", label)
+				continue
+			}
+			fmt.Fprintf(w, "  %-12s %q\n", label, input[m[2*i]:m[2*i+1]])
+		}
+	}
+	if s.replace {
+		fmt.Fprintf(w, "replaced: %s\n", s.re.ReplaceAllString(input, s.template))
+	}
+}
+
+// highlight marks the matches in input.
+func (s *Session) highlight(input string, matches [][]int) string {
+	open, close := "[", "]"
+	if s.Color {
+		open, close = "\x1b[1;32m", "\x1b[0m"
+	}
+	var sb strings.Builder
+	last := 0
+	for _, m := range matches {
+		sb.WriteString(input[last:m[0]])
+		sb.WriteString(open + input[m[0]:m[1]] + close)
+		last = m[1]
+	}
+	sb.WriteString(input[last:])
+	return sb.String()
+}
+
+// Snippet returns Go code using the pattern, and the replacement if one is set.
+func (s *Session) Snippet() string {
+	var sb strings.Builder
+	sb.WriteString("re := regexp.MustCompile(" + quote(s.re.String()) + ")\n")
+	if names := s.re.SubexpNames(); len(names) > 1 {
+		sb.WriteString("for _, m := range re.FindAllStringSubmatch(input, -1) {\n")
+		var vars []string
+		for i, name := range names[1:] {
+			if name == "" {
+				name = fmt.Sprintf("group%d", i+1)
+			}
+			fmt.Fprintf(&sb, "\t%s := m[%d]\n", identifier(name), i+1)
+			vars = append(vars, identifier(name))
+		}
+		sb.WriteString("\tfmt.Println(" + strings.Join(vars, ", ") + ")\n")
+		sb.WriteString("}\n")
+	} else {
+		sb.WriteString("matches := re.FindAllString(input, -1)\n")
+		sb.WriteString("fmt.Println(matches)\n")
+	}
+	if s.replace {
+		sb.WriteString("result := re.ReplaceAllString(input, " + quote(s.template) + ")\n")
+		sb.WriteString("fmt.Println(result)\n")
+	}
+	return sb.String()
+}
+
+// quote writes s as a raw string literal when it can, those are easier to read for patterns.
+func quote(s string) string {
+	if !strings.Contains(s, "`") {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
+
+func identifier(name string) string {
+	var sb strings.Builder
+	for i, r := range name {
+		if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9' {
+			sb.WriteRune(r)
+		}
+	}
+	if sb.Len() == 0 {
+		return "group"
+	}
+	return sb.String()
+}
@@ -0,0 +1,171 @@
+package retest
+
+import (
+	"bytes"
+	"errors"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+)
+
+func run(s *Session, lines ...string) string {
+	var buf bytes.Buffer
+	for _, line := range lines {
+		s.Handle(&buf, line)
+	}
+	return buf.String()
+}
+
+func TestMatchesAndGroups(t *testing.T) {
+	s := &Session{}
+	out := run(s, `:p (?P<id>\d+),\s*(?P<quantity>\d+)`, "112, 2 and 94,3")
+
+	for _, expected := range []string{
+		"groups:  id, quantity",
+		"[112, 2] and [94,3]",
+		`match 1: "112, 2" at 0-6`,
+		`1 id         "112"`,
+		`2 quantity   "3"`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Output should contain %q, Actual:\n%s", expected, out)
+		}
+	}
+
+	if out := run(s, "no numbers"); out != "no match\n" {
+		t.Errorf("Output, Actual: %q, Expected: %q", out, "no match\n")
+	}
+}
+
+func TestInputWithSpaces(t *testing.T) {
+	s := &Session{}
+	out := run(s, `:p \w+ \w+`, "Romeo and Juliet")
+	if !strings.Contains(out, "[Romeo and] Juliet") {
+		t.Errorf("The whole line should be the input, Actual:\n%s", out)
+	}
+}
+
+func TestReplacePreview(t *testing.T) {
+	s := &Session{}
+	out := run(s, `:p (\<\/?)(title)(\>)`, ":r ${1}name${3}", "<title>Hamlet</title>")
+	if !strings.Contains(out, "replaced: <name>Hamlet</name>") {
+		t.Errorf("Replace preview was incorrect, Actual:\n%s", out)
+	}
+}
+
+func TestFlags(t *testing.T) {
+	s := &Session{}
+	out := run(s, ":p hamlet", ":f i", "HAMLET")
+	if !strings.Contains(out, "[HAMLET]") {
+		t.Errorf("Flag i should ignore case, Actual:\n%s", out)
+	}
+	if err := s.SetFlags("x"); err == nil {
+		t.Errorf("SetFlags should reject unknown flags")
+	}
+}
+
+func TestPatternError(t *testing.T) {
+	s := &Session{}
+	s.SetPattern(`\d+`)
+
+	err := s.SetPattern(`ab(c`)
+	var pe *PatternError
+	if !errors.As(err, &pe) {
+		t.Fatalf("SetPattern should return a *PatternError, Actual: %v", err)
+	}
+	if pe.Pos != 2 {
+		t.Errorf("Pos, Actual: %d, Expected: %d", pe.Pos, 2)
+	}
+
+	err = s.SetPattern(`a+b**`)
+	errors.As(err, &pe)
+	if pe.Pos != 3 || pe.Caret() != "a+b**\n   ^" {
+		t.Errorf("Caret was incorrect, Actual: %d\n%s", pe.Pos, pe.Caret())
+	}
+
+	cases := []struct {
+		pattern string
+		flags   string
+		pos     int
+	}{
+		{`ab(c`, "i", 2},
+		{`(a)(b(c)`, "", 3},
+		{`a)b`, "", 1},
+		{`(a))(b`, "is", 3},
+		{`\((a`, "", 2},
+		{`[(](a`, "", 3},
+		{`[]()](`, "", 5},
+		{`a+b**`, "i", 3},
+	}
+	for _, c := range cases {
+		s := &Session{}
+		s.SetFlags(c.flags)
+		err := s.SetPattern(c.pattern)
+		if !errors.As(err, &pe) || pe.Pos != c.pos {
+			t.Errorf("Pos of %q with flags %q was incorrect, Actual: %v, Expected: %d", c.pattern, c.flags, err, c.pos)
+		}
+	}
+
+	if out := run(s, "42"); !strings.Contains(out, "[42]") {
+		t.Errorf("A bad pattern should keep the previous one, Actual:\n%s", out)
+	}
+}
+
+func TestHistoryAndExport(t *testing.T) {
+	s := &Session{}
+	s.LoadHistory([]string{":p old"})
+	run(s, `:p (?P<id>\d+)-(\w+)`, ":r <${id}>", "12-ab", ":history")
+
+	history := s.History()
+	if len(history) != 4 || history[0] != ":p old" || history[3] != "12-ab" {
+		t.Errorf("History was incorrect, Actual: %q", history)
+	}
+
+	expected := "re := regexp.MustCompile(`(?P<id>\\d+)-(\\w+)`)\n" +
+		"for _, m := range re.FindAllStringSubmatch(input, -1) {\n" +
+		"\tid := m[1]\n" +
+		"\tgroup2 := m[2]\n" +
+		"\tfmt.Println(id, group2)\n" +
+		"}\n" +
+		"result := re.ReplaceAllString(input, `<${id}>`)\n" +
+		"fmt.Println(result)\n"
+	if actual := s.Snippet(); actual != expected {
+		t.Errorf("Snippet was incorrect, Actual:\n%s\nExpected:\n%s", actual, expected)
+	}
+}
+
+// TestSnippetCompiles type checks the snippets, which catches variables
+// that are declared and not used.
+func TestSnippetCompiles(t *testing.T) {
+	for _, lines := range [][]string{
+		{`:p \d+`},
+		{`:p (?P<year>\d{4})-(\d{2})`, ":r $2/${year}"},
+	} {
+		s := &Session{}
+		run(s, lines...)
+		src := "package p\n\nimport (\n\t\"fmt\"\n\t\"regexp\"\n)\n\nfunc f(input string) {\n" + s.Snippet() + "}\n"
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "snippet.go", src, 0)
+		if err != nil {
+			t.Fatalf("Snippet doesn't parse: %v\n%s", err, src)
+		}
+		conf := types.Config{Importer: importer.Default()}
+		if _, err := conf.Check("p", fset, []*ast.File{file}, nil); err != nil {
+			t.Errorf("Snippet doesn't compile: %v\n%s", err, src)
+		}
+	}
+}
+
+func TestQuit(t *testing.T) {
+	s := &Session{}
+	if s.Handle(&bytes.Buffer{}, ":quit") {
+		t.Errorf("Handle should return false on :quit")
+	}
+	if out := run(s, ":nope"); !strings.Contains(out, "unknown command :nope") {
+		t.Errorf("Unknown command, Actual: %q", out)
+	}
+}
@@ -0,0 +1,166 @@
+package xmltransform
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+)
+
+// Record is one repeating element, like a <book>, with the text of its child elements in order.
+type Record struct {
+	Fields []Field
+}
+
+type Field struct {
+	Name  string
+	Value string
+}
+
+// Get returns the value of the field called name.
+func (r Record) Get(name string) (string, bool) {
+	for _, f := range r.Fields {
+		if f.Name == name {
+			return f.Value, true
+		}
+	}
+	return "", false
+}
+
+// Records yields every element called name in the document, one at a
+// time. Attributes of the element and its children become fields too,
+// named like "@id" and "cost@currency".
+func Records(r io.Reader, name string) iter.Seq2[Record, error] {
+	return func(yield func(Record, error) bool) {
+		dec := xml.NewDecoder(r)
+		var current *Record
+		var field string
+		var text strings.Builder
+		depth := 0
+		for {
+			tok, err := dec.Token()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				yield(Record{}, err)
+				return
+			}
+			switch t := tok.(type) {
+			case xml.StartElement:
+				if current == nil {
+					if t.Name.Local == name {
+						current, depth = &Record{}, 0
+						for _, a := range t.Attr {
+							current.Fields = append(current.Fields, Field{"@" + a.Name.Local, a.Value})
+						}
+					}
+					continue
+				}
+				depth++
+				if depth == 1 {
+					field = t.Name.Local
+					text.Reset()
+					for _, a := range t.Attr {
+						current.Fields = append(current.Fields, Field{field + "@" + a.Name.Local, a.Value})
+					}
+				}
+			case xml.CharData:
+				if current != nil && depth >= 1 {
+					text.Write(t)
+				}
+			case xml.EndElement:
+				if current == nil {
+					continue
+				}
+				if depth == 0 {
+					if !yield(*current, nil) {
+						return
+					}
+					current = nil
+					continue
+				}
+				if depth == 1 {
+					current.Fields = append(current.Fields, Field{field, strings.TrimSpace(text.String())})
+				}
+				depth--
+			}
+		}
+	}
+}
+
+// WriteJSON writes the records as a JSON array of objects, keeping the order of the fields.
+func WriteJSON(w io.Writer, records iter.Seq2[Record, error]) error {
+	out := bufio.NewWriter(w)
+	out.WriteString("[")
+	first := true
+	for record, err := range records {
+		if err != nil {
+			return err
+		}
+		if !first {
+			out.WriteString(",")
+		}
+		first = false
+		out.WriteString("\n  {")
+		for i, f := range record.Fields {
+			if i > 0 {
+				out.WriteString(", ")
+			}
+			name, _ := json.Marshal(f.Name)
+			value, _ := json.Marshal(f.Value)
+			fmt.Fprintf(out, "%s: %s", name, value)
+		}
+		out.WriteString("}")
+	}
+	if !first {
+		out.WriteString("\n")
+	}
+	out.WriteString("]\n")
+	return out.Flush()
+}
+
+// WriteCSV writes the records as CSV with a header. The columns are the
+// fields of the first record; a later record with another field is an error,
+// a missing field is left empty.
+func WriteCSV(w io.Writer, records iter.Seq2[Record, error]) error {
+	cw := csv.NewWriter(w)
+	var columns []string
+	n := 0
+	for record, err := range records {
+		if err != nil {
+			return err
+		}
+		n++
+		if columns == nil {
+			for _, f := range record.Fields {
+				columns = append(columns, f.Name)
+			}
+			cw.Write(columns)
+		}
+		row := make([]string, len(columns))
+		for _, f := range record.Fields {
+			i := indexOf(columns, f.Name)
+			if i < 0 {
+				return fmt.Errorf("xmltransform: record %d has field %q, which the first record doesn't have", n, f.Name)
+			}
+			row[i] = f.Value
+		}
+		cw.Write(row)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func indexOf(list []string, s string) int {
+	for i, item := range list {
+		if item == s {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,203 @@
+// Package xmltransform changes XML documents token by token with
+// encoding/xml, so it understands attributes, namespaces, CDATA and
+// self-closing tags, which a regular expression doesn't. Documents are
+// streamed, they can be bigger than memory.
+package xmltransform
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Mapping says what to rename. Element keys are a local name like "title",
+// matching any namespace prefix, or a prefixed name like "dc:title".
+// Attribute keys are an attribute name, or "element@attribute" to only
+// rename it on that element.
+type Mapping struct {
+	Elements   map[string]string `json:"elements"`
+	Attributes map[string]string `json:"attributes"`
+}
+
+// LoadMapping reads a mapping from a JSON file like
+// {"elements": {"title": "name"}, "attributes": {"book@lang": "language"}}.
+func LoadMapping(path string) (Mapping, error) {
+	var m Mapping
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return m, err
+	}
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&m); err != nil {
+		return m, fmt.Errorf("mapping %s: %w", path, err)
+	}
+	return m, nil
+}
+
+func (m Mapping) element(name xml.Name) (xml.Name, bool) {
+	if to, ok := m.Elements[qualified(name)]; ok {
+		return split(to), true
+	}
+	if to, ok := m.Elements[name.Local]; ok {
+		// keep the prefix unless the new name has its own
+		if renamed := split(to); renamed.Space != "" {
+			return renamed, true
+		}
+		return xml.Name{Space: name.Space, Local: to}, true
+	}
+	return name, false
+}
+
+func (m Mapping) attribute(element xml.Name, attr xml.Name) (xml.Name, bool) {
+	for _, key := range []string{
+		qualified(element) + "@" + qualified(attr),
+		element.Local + "@" + qualified(attr),
+		qualified(attr),
+	} {
+		if to, ok := m.Attributes[key]; ok {
+			return split(to), true
+		}
+	}
+	return attr, false
+}
+
+// qualified writes a raw name as it appears in the document, prefix:local.
+func qualified(n xml.Name) string {
+	if n.Space == "" {
+		return n.Local
+	}
+	return n.Space + ":" + n.Local
+}
+
+func split(s string) xml.Name {
+	if prefix, local, ok := strings.Cut(s, ":"); ok {
+		return xml.Name{Space: prefix, Local: local}
+	}
+	return xml.Name{Local: s}
+}
+
+// Rename copies the XML document in r to w, renaming elements and
+// attributes as m says. Everything else, like whitespace, comments, CDATA
+// sections and entities, is copied byte for byte. Tags that are renamed
+// are written again, with their attributes separated by single spaces.
+// On an error, w holds the document up to where it went wrong.
+func Rename(w io.Writer, r io.Reader, m Mapping) error {
+	out := bufio.NewWriter(w)
+	src := &recorder{r: bufio.NewReader(r)}
+	dec := xml.NewDecoder(src)
+	dec.Strict = true
+
+	var prev int64
+	// the open elements with their new names, for their end tags
+	type element struct{ from, to xml.Name }
+	var open []element
+	for {
+		tok, err := dec.RawToken()
+		if err == io.EOF {
+			if len(open) > 0 {
+				return fmt.Errorf("xmltransform: <%s> isn't closed", qualified(open[len(open)-1].from))
+			}
+			break
+		}
+		if err != nil {
+			return err
+		}
+		offset := dec.InputOffset()
+		raw := src.take(offset - prev)
+		prev = offset
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			name, renamed := m.element(t.Name)
+			attrs := make([]xml.Attr, len(t.Attr))
+			for i, a := range t.Attr {
+				attrs[i] = a
+				if newName, ok := m.attribute(t.Name, a.Name); ok {
+					attrs[i].Name = newName
+					renamed = true
+				}
+			}
+			selfClosing := strings.HasSuffix(string(raw), "/>")
+			if !selfClosing {
+				open = append(open, element{t.Name, name})
+			}
+			if renamed {
+				writeStart(out, name, attrs, selfClosing)
+				continue
+			}
+		case xml.EndElement:
+			if len(raw) == 0 {
+				// the end of a self-closing tag, already written
+				continue
+			}
+			if len(open) == 0 {
+				return fmt.Errorf("xmltransform: unexpected </%s>", qualified(t.Name))
+			}
+			e := open[len(open)-1]
+			open = open[:len(open)-1]
+			if e.from != t.Name {
+				return fmt.Errorf("xmltransform: <%s> is closed by </%s>", qualified(e.from), qualified(t.Name))
+			}
+			if e.to != t.Name {
+				fmt.Fprintf(out, "</%s>", qualified(e.to))
+				continue
+			}
+		}
+		out.Write(raw)
+	}
+	return out.Flush()
+}
+
+func writeStart(w *bufio.Writer, name xml.Name, attrs []xml.Attr, selfClosing bool) {
+	w.WriteString("<" + qualified(name))
+	for _, a := range attrs {
+		w.WriteString(" " + qualified(a.Name) + `="`)
+		xml.EscapeText(w, []byte(a.Value))
+		w.WriteString(`"`)
+	}
+	if selfClosing {
+		w.WriteString("/>")
+	} else {
+		w.WriteString(">")
+	}
+}
+
+// recorder keeps the bytes the decoder has read but which haven't been
+// taken yet. It's an io.ByteReader, so the decoder doesn't read ahead and
+// InputOffset tells exactly which bytes make up a token.
+type recorder struct {
+	r   *bufio.Reader
+	buf []byte
+}
+
+func (r *recorder) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	b, err := r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	p[0] = b
+	return 1, nil
+}
+
+func (r *recorder) ReadByte() (byte, error) {
+	b, err := r.r.ReadByte()
+	if err == nil {
+		r.buf = append(r.buf, b)
+	}
+	return b, err
+}
+
+// take removes the next n recorded bytes and returns them.
+func (r *recorder) take(n int64) []byte {
+	raw := append([]byte(nil), r.buf[:n]...)
+	r.buf = r.buf[:copy(r.buf, r.buf[n:])]
+	return raw
+}
@@ -0,0 +1,127 @@
+package xmltransform
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+var books = Mapping{Elements: map[string]string{"title": "name", "cost": "price"}}
+
+func rename(t *testing.T, doc string, m Mapping) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := Rename(&buf, strings.NewReader(doc), m); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestRenameKeepsFormatting(t *testing.T) {
+	doc := `<?xml version="1.0"?>
+<!-- a <title> in a comment stays -->
+<books>
+    <book id="1">
+      <title>The title is Romeo &amp; Juliet</title>
+      <cost  currency='USD'>17</cost>
+      <note><![CDATA[<title> in CDATA stays]]></note>
+      <cost/>
+    </book>
+</books>`
+	expected := `<?xml version="1.0"?>
+<!-- a <title> in a comment stays -->
+<books>
+    <book id="1">
+      <name>The title is Romeo &amp; Juliet</name>
+      <price currency="USD">17</price>
+      <note><![CDATA[<title> in CDATA stays]]></note>
+      <price/>
+    </book>
+</books>`
+	if actual := rename(t, doc, books); actual != expected {
+		t.Errorf("Rename was incorrect,\nActual:\n%s\nExpected:\n%s", actual, expected)
+	}
+}
+
+func TestRenameNamespacesAndAttributes(t *testing.T) {
+	doc := `<dc:record xmlns:dc="http://purl.org/dc/elements/1.1/"><dc:title lang="en">Hamlet</dc:title><title>x</title><book lang="en" cost="15"/></dc:record>`
+	m := Mapping{
+		Elements:   map[string]string{"dc:title": "dc:name", "title": "heading"},
+		Attributes: map[string]string{"book@lang": "language", "cost": "price"},
+	}
+	expected := `<dc:record xmlns:dc="http://purl.org/dc/elements/1.1/"><dc:name lang="en">Hamlet</dc:name><heading>x</heading><book language="en" price="15"/></dc:record>`
+	if actual := rename(t, doc, m); actual != expected {
+		t.Errorf("Rename was incorrect,\nActual:   %s\nExpected: %s", actual, expected)
+	}
+}
+
+func TestRenameLargeDocument(t *testing.T) {
+	var doc strings.Builder
+	doc.WriteString("<books>\n")
+	for i := 0; i < 20000; i++ {
+		doc.WriteString("  <book><title>Hamlet</title><cost>15</cost></book>\n")
+	}
+	doc.WriteString("</books>\n")
+
+	out := rename(t, doc.String(), books)
+	if strings.Count(out, "<name>Hamlet</name>") != 20000 || strings.Contains(out, "title") {
+		t.Errorf("Not every element was renamed")
+	}
+	if len(out) != doc.Len() {
+		t.Errorf("Length, Actual: %d, Expected: %d", len(out), doc.Len())
+	}
+}
+
+func TestRenameInvalid(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Rename(&buf, strings.NewReader("<books><book></books>"), books); err == nil {
+		t.Errorf("Rename should fail on mismatched tags")
+	}
+}
+
+const booksXML = `<books>
+    <book id="1">
+      <author>Shakespeare</author>
+      <title>Romeo and Juliet</title>
+      <cost currency="USD">17</cost>
+    </book>
+    <book id="2">
+      <author>Shakespeare</author>
+      <title>Hamlet</title>
+      <cost currency="USD">15</cost>
+    </book>
+</books>`
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, Records(strings.NewReader(booksXML), "book")); err != nil {
+		t.Fatal(err)
+	}
+	var out []map[string]string
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("Output isn't JSON: %v\n%s", err, buf.String())
+	}
+	if len(out) != 2 || out[1]["title"] != "Hamlet" || out[1]["@id"] != "2" || out[1]["cost@currency"] != "USD" {
+		t.Errorf("WriteJSON was incorrect, Actual: %v", out)
+	}
+	if !strings.Contains(buf.String(), `{"@id": "1", "author": "Shakespeare", "title": "Romeo and Juliet", "cost@currency": "USD", "cost": "17"}`) {
+		t.Errorf("Fields should keep their order, Actual: %s", buf.String())
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, Records(strings.NewReader(booksXML), "book")); err != nil {
+		t.Fatal(err)
+	}
+	expected := "@id,author,title,cost@currency,cost\n1,Shakespeare,Romeo and Juliet,USD,17\n2,Shakespeare,Hamlet,USD,15\n"
+	if buf.String() != expected {
+		t.Errorf("WriteCSV was incorrect, Actual:\n%s\nExpected:\n%s", buf.String(), expected)
+	}
+
+	extra := `<books><book><title>a</title></book><book><title>b</title><pages>1</pages></book></books>`
+	if err := WriteCSV(&buf, Records(strings.NewReader(extra), "book")); err == nil {
+		t.Errorf("WriteCSV should fail on a field the first record doesn't have")
+	}
+}
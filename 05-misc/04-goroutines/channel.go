@@ -1,38 +1,38 @@
+//go:build ignore
+
+// Run with: go run channel.go
 package main
 
 import (
+	"context"
 	"fmt"
 	"time"
+
+	"goroutines/concurrency"
 )
 
-func run(ch chan int, no int) {
-	ch <- no
+func run(no int) func(send func(int) bool) {
+	return func(send func(int) bool) {
+		send(no)
+	}
 }
 
 func main() {
-	ch := make(chan int)
-
-	go run(ch, 1)
-	go run(ch, 2)
-	time.Sleep(1 * time.Second)
+	// wait for at most a second, but not a moment longer than needed
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
 
-block:
-	for {
-		select {
-		case x, ok := <-ch:
-			if ok {
-				fmt.Println(x)
-			} else {
-				fmt.Println("channel closed")
+	// the channel is closed when both producers are done
+	ch := concurrency.Produce(ctx, run(1), run(2))
 
-				break block
-			}
-		default:
-			fmt.Println("No value to read, exiting")
-
-			break block
-		}
+	values, err := concurrency.Collect(ctx, ch)
+	for _, x := range values {
+		fmt.Println(x)
+	}
+	if err != nil {
+		fmt.Println("Gave up waiting:", err)
+	} else {
+		fmt.Println("channel closed")
 	}
 	fmt.Println("Done with values")
-
 }
@@ -1,3 +1,6 @@
+//go:build ignore
+
+// Run with: go run channel1.go
 package main
 
 import (
@@ -0,0 +1,192 @@
+// Command gosearch finds files by name, or lines by content, concurrently.
+//
+// Usage:
+//
+//	gosearch [flags] [root]
+//
+// Examples:
+//
+//	gosearch -name test2.txt
+//	gosearch -glob '*.go' -grep SearchFiles -C 2
+//	gosearch -e 'func \w+\(' -json .
+//
+// With -json, every result is printed as one JSON object per line, errors
+// have an "error" field. The exit status is 0 when something was found,
+// 1 when nothing was found and 2 on errors.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"goroutines/search"
+)
+
+type jsonResult struct {
+	search.Match
+	IsDir bool   `json:"dir,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+func main() {
+	name := flag.String("name", "", "match files with exactly this name")
+	glob := flag.String("glob", "", "match file names with a glob, like '*.txt'")
+	pathRegex := flag.String("regex", "", "match file paths with a regular expression")
+	literal := flag.String("grep", "", "search file contents for this text")
+	pattern := flag.String("e", "", "search file contents with this regular expression")
+	ignoreCase := flag.Bool("i", false, "ignore case when searching contents")
+	contextLines := flag.Int("C", 0, "lines of context around content matches")
+	maxSize := flag.Int64("max-size", search.DefaultMaxSize, "skip files larger than this many bytes, -1 for no limit (lines are still capped at 10 MB)")
+	workers := flag.Int("workers", 0, "number of concurrent workers, defaults to the number of CPUs")
+	first := flag.Bool("first", false, "stop after the first result")
+	noIgnore := flag.Bool("no-ignore", false, "don't skip files listed in .gitignore")
+	dirs := flag.Bool("dirs", false, "let directory names match too")
+	asJSON := flag.Bool("json", false, "print results as JSON Lines")
+	flag.Parse()
+
+	if *literal != "" && *pattern != "" {
+		fmt.Fprintln(os.Stderr, "gosearch: give -grep or -e, not both")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	root := "."
+	if flag.NArg() > 0 {
+		root = flag.Arg(0)
+	}
+
+	files, err := fileMatcher(*name, *glob, *pathRegex)
+	if err != nil {
+		fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	out := &printer{json: *asJSON, enc: json.NewEncoder(os.Stdout)}
+	if *literal != "" || *pattern != "" {
+		opts := search.GrepOptions{
+			Pattern:    *literal,
+			IgnoreCase: *ignoreCase,
+			Context:    *contextLines,
+			MaxSize:    *maxSize,
+			Files:      files,
+			Workers:    *workers,
+			FirstMatch: *first,
+			NoIgnore:   *noIgnore,
+		}
+		if *pattern != "" {
+			opts.Pattern, opts.Regex = *pattern, true
+		}
+		matches, err := search.Grep(ctx, root, opts)
+		if err != nil {
+			fatal(err)
+		}
+		for m := range matches {
+			out.match(m)
+		}
+	} else {
+		if files == nil {
+			fmt.Fprintln(os.Stderr, "gosearch: give -name, -glob, -regex, -grep or -e")
+			flag.Usage()
+			os.Exit(2)
+		}
+		opts := search.Options{Workers: *workers, FirstMatch: *first, IncludeDirs: *dirs, GitIgnore: !*noIgnore}
+		for res := range search.Search(ctx, root, files, opts) {
+			out.result(res)
+		}
+	}
+
+	switch {
+	case out.errors > 0:
+		os.Exit(2)
+	case out.found == 0:
+		os.Exit(1)
+	}
+}
+
+// fileMatcher combines the name filters, nil means no filter was given.
+func fileMatcher(name, glob, pathRegex string) (search.Matcher, error) {
+	var matchers []search.Matcher
+	if name != "" {
+		matchers = append(matchers, search.Name(name))
+	}
+	if glob != "" {
+		m, err := search.Glob(glob)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, m)
+	}
+	if pathRegex != "" {
+		m, err := search.Regex(pathRegex)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, m)
+	}
+	if len(matchers) == 0 {
+		return nil, nil
+	}
+	return search.All(matchers...), nil
+}
+
+type printer struct {
+	json   bool
+	enc    *json.Encoder
+	found  int
+	errors int
+}
+
+func (p *printer) result(res search.Result) {
+	if res.Err != nil {
+		p.error(res.Path, res.Err)
+		return
+	}
+	p.found++
+	if p.json {
+		p.enc.Encode(jsonResult{Match: search.Match{Path: res.Path}, IsDir: res.IsDir})
+		return
+	}
+	fmt.Println(res.Path)
+}
+
+func (p *printer) match(m search.Match) {
+	if m.Err != nil {
+		p.error(m.Path, m.Err)
+		return
+	}
+	p.found++
+	if p.json {
+		p.enc.Encode(jsonResult{Match: m})
+		return
+	}
+	for i, text := range m.Before {
+		fmt.Printf("%s-%d-%s\n", m.Path, m.Line-len(m.Before)+i, text)
+	}
+	fmt.Printf("%s:%d:%d:%s\n", m.Path, m.Line, m.Column, m.Text)
+	for i, text := range m.After {
+		fmt.Printf("%s-%d-%s\n", m.Path, m.Line+i+1, text)
+	}
+	if len(m.Before) > 0 || len(m.After) > 0 {
+		fmt.Println("--")
+	}
+}
+
+func (p *printer) error(path string, err error) {
+	p.errors++
+	if p.json {
+		p.enc.Encode(jsonResult{Match: search.Match{Path: path}, Error: err.Error()})
+		return
+	}
+	fmt.Fprintln(os.Stderr, "gosearch:", err)
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "gosearch:", err)
+	os.Exit(2)
+}
@@ -0,0 +1,81 @@
+package concurrency
+
+import (
+	"context"
+	"sync"
+)
+
+// Produce runs every producer on its own goroutine and returns the channel
+// they send to. The channel is closed once all producers have returned, so
+// readers can range over it instead of guessing when the values are done.
+//
+// send blocks until the value is read and returns false when ctx is done,
+// at which point the producer should return.
+func Produce[T any](ctx context.Context, producers ...func(send func(T) bool)) <-chan T {
+	out := make(chan T)
+	send := func(v T) bool {
+		select {
+		case out <- v:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(len(producers))
+	for _, produce := range producers {
+		go func(produce func(send func(T) bool)) {
+			defer wg.Done()
+			produce(send)
+		}(produce)
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// Merge forwards the values of all ins to one channel, which is closed after
+// every input is closed, or when ctx is done.
+func Merge[T any](ctx context.Context, ins ...<-chan T) <-chan T {
+	out := make(chan T)
+	var wg sync.WaitGroup
+	wg.Add(len(ins))
+	for _, in := range ins {
+		go func(in <-chan T) {
+			defer wg.Done()
+			for v := range in {
+				select {
+				case out <- v:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(in)
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// Collect reads ch until it's closed and returns every value. If ctx is done
+// first, it returns what it got so far together with ctx.Err(); use
+// context.WithTimeout to bound the wait.
+func Collect[T any](ctx context.Context, ch <-chan T) ([]T, error) {
+	var values []T
+	for {
+		select {
+		case v, ok := <-ch:
+			if !ok {
+				return values, nil
+			}
+			values = append(values, v)
+		case <-ctx.Done():
+			return values, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package concurrency
+
+import (
+	"context"
+	"math/rand"
+	"runtime"
+	"sort"
+	"testing"
+	"time"
+)
+
+// jitter yields or sleeps briefly at random, to shake up the scheduling.
+func jitter(r *rand.Rand) {
+	switch r.Intn(3) {
+	case 0:
+		runtime.Gosched()
+	case 1:
+		time.Sleep(time.Duration(r.Intn(50)) * time.Microsecond)
+	}
+}
+
+func TestProduceNoLostValues(t *testing.T) {
+	const producers, perProducer = 16, 50
+	for round := 0; round < 50; round++ {
+		var funcs []func(send func(int) bool)
+		for p := 0; p < producers; p++ {
+			p := p
+			r := rand.New(rand.NewSource(int64(round*producers + p)))
+			funcs = append(funcs, func(send func(int) bool) {
+				for i := 0; i < perProducer; i++ {
+					jitter(r)
+					if !send(p*perProducer + i) {
+						return
+					}
+				}
+			})
+		}
+
+		values, err := Collect(context.Background(), Produce(context.Background(), funcs...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkAll(t, values, producers*perProducer)
+	}
+}
+
+func TestMergeNoLostValues(t *testing.T) {
+	for round := 0; round < 50; round++ {
+		var ins []<-chan int
+		for c := 0; c < 8; c++ {
+			ch := make(chan int)
+			ins = append(ins, ch)
+			go func(c int, ch chan int) {
+				r := rand.New(rand.NewSource(int64(round*8 + c)))
+				defer close(ch)
+				for i := 0; i < 25; i++ {
+					jitter(r)
+					ch <- c*25 + i
+				}
+			}(c, ch)
+		}
+
+		values, err := Collect(context.Background(), Merge(context.Background(), ins...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkAll(t, values, 200)
+	}
+}
+
+func checkAll(t *testing.T, values []int, n int) {
+	t.Helper()
+	if len(values) != n {
+		t.Fatalf("Value count, Actual: %d, Expected: %d", len(values), n)
+	}
+	sort.Ints(values)
+	for i, v := range values {
+		if v != i {
+			t.Fatalf("Lost or duplicated value at %d, Actual: %d", i, v)
+		}
+	}
+}
+
+func TestCollectTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	slow := func(send func(int) bool) {
+		send(1)
+		time.Sleep(time.Second)
+		send(2)
+	}
+	values, err := Collect(ctx, Produce(ctx, slow))
+	if err != context.DeadlineExceeded {
+		t.Errorf("Collect, Actual: %v, Expected: %v", err, context.DeadlineExceeded)
+	}
+	if len(values) != 1 || values[0] != 1 {
+		t.Errorf("Collect should keep the values it got, Actual: %v", values)
+	}
+}
+
+func TestProduceStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan bool)
+	ch := Produce(ctx, func(send func(int) bool) {
+		for i := 0; ; i++ {
+			if !send(i) {
+				stopped <- true
+				return
+			}
+		}
+	})
+	<-ch
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("producer kept running after cancel")
+	}
+	// the channel is closed once the producer returned
+	for range ch {
+	}
+}
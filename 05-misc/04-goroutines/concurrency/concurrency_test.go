@@ -0,0 +1,207 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var errOdd = errors.New("odd number")
+
+func TestPool(t *testing.T) {
+	var running, most int32
+	double := func(ctx context.Context, n int) (int, error) {
+		now := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			old := atomic.LoadInt32(&most)
+			if now <= old || atomic.CompareAndSwapInt32(&most, old, now) {
+				break
+			}
+		}
+		if n%7 == 0 {
+			return 0, fmt.Errorf("%d: %w", n, errOdd)
+		}
+		return n * 2, nil
+	}
+
+	pool := NewPool(context.Background(), 3, double)
+	for i := 1; i <= 50; i++ {
+		if err := pool.Submit(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	results := pool.Wait()
+
+	if len(results) != 50 {
+		t.Fatalf("Result count was incorrect, Actual: %d, Expected: 50", len(results))
+	}
+	for i, r := range results {
+		if r.Task != i+1 {
+			t.Fatalf("Results are out of order at %d, Actual: %d", i, r.Task)
+		}
+		if r.Err == nil && r.Value != r.Task*2 {
+			t.Errorf("Value was incorrect, Actual: %d, Expected: %d", r.Value, r.Task*2)
+		}
+	}
+	if len(results.Values()) != 43 {
+		t.Errorf("Values count was incorrect, Actual: %d, Expected: 43", len(results.Values()))
+	}
+	if err := results.Err(); !errors.Is(err, errOdd) || len(err.(Errors)) != 7 {
+		t.Errorf("Err was incorrect, Actual: %v", err)
+	}
+	if most > 3 {
+		t.Errorf("Pool ran %d tasks at once, Expected at most 3", most)
+	}
+	if err := pool.Submit(1); err != ErrPoolClosed {
+		t.Errorf("Submit after Wait, Actual: %v, Expected: %v", err, ErrPoolClosed)
+	}
+}
+
+func TestPoolConcurrentSubmit(t *testing.T) {
+	pool := NewPool(context.Background(), 4, func(ctx context.Context, n int) (int, error) { return n, nil })
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				pool.Submit(i*100 + j)
+			}
+		}(i)
+	}
+	wg.Wait()
+	values := pool.Wait().Values()
+	sort.Ints(values)
+	for i, v := range values {
+		if v != i {
+			t.Fatalf("Lost or duplicated value at %d, Actual: %d", i, v)
+		}
+	}
+}
+
+func TestPoolCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	pool := NewPool(ctx, 1, func(ctx context.Context, n int) (int, error) {
+		close(started)
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	go pool.Submit(1)
+	<-started
+	cancel()
+	if err := pool.Submit(2); err != context.Canceled {
+		t.Errorf("Submit after cancel, Actual: %v, Expected: %v", err, context.Canceled)
+	}
+	if err := pool.Wait().Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait after cancel, Actual: %v", err)
+	}
+}
+
+func TestGroupFirstError(t *testing.T) {
+	g, ctx := WithContext(context.Background())
+	g.SetLimit(2)
+	var cancelled int32
+	for i := 0; i < 5; i++ {
+		i := i
+		g.Go(func() error {
+			if i == 0 {
+				return errOdd
+			}
+			select {
+			case <-ctx.Done():
+				atomic.AddInt32(&cancelled, 1)
+				return ctx.Err()
+			case <-time.After(5 * time.Second):
+				return nil
+			}
+		})
+	}
+	if err := g.Wait(); err != errOdd {
+		t.Errorf("Wait, Actual: %v, Expected: %v", err, errOdd)
+	}
+	if cancelled != 4 {
+		t.Errorf("Cancelled functions, Actual: %d, Expected: 4", cancelled)
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	g, ctx := WithContext(context.Background())
+	numbers := make([]int, 100)
+	for i := range numbers {
+		numbers[i] = i
+	}
+
+	src := Generate(ctx, numbers...)
+	squares := Stage(g, ctx, src, 4, func(ctx context.Context, n int) (int, error) { return n * n, nil })
+	parts := FanOut(ctx, squares, 3)
+	merged := FanIn(ctx, parts...)
+
+	var got []int
+	for v := range merged {
+		got = append(got, v)
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	sort.Ints(got)
+	if len(got) != 100 {
+		t.Fatalf("Value count, Actual: %d, Expected: 100", len(got))
+	}
+	for i, v := range got {
+		if v != i*i {
+			t.Fatalf("Value at %d, Actual: %d, Expected: %d", i, v, i*i)
+		}
+	}
+}
+
+func TestPipelineError(t *testing.T) {
+	g, ctx := WithContext(context.Background())
+	src := Generate(ctx, 1, 2, 3, 4, 5, 6, 7, 8)
+	out := Stage(g, ctx, src, 2, func(ctx context.Context, n int) (int, error) {
+		if n == 3 {
+			return 0, errOdd
+		}
+		return n, nil
+	})
+	for range out {
+	}
+	if err := g.Wait(); err != errOdd {
+		t.Errorf("Wait, Actual: %v, Expected: %v", err, errOdd)
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	l := NewLimiter(100, 2)
+	if !l.Allow() || !l.Allow() {
+		t.Fatal("the burst should be allowed")
+	}
+	if l.Allow() {
+		t.Fatal("the bucket should be empty")
+	}
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// three tokens at 100 per second take about 30ms
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Wait did not limit, took %v", elapsed)
+	}
+
+	slow := NewLimiter(0.1, 1)
+	slow.Allow()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := slow.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Wait, Actual: %v, Expected: %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,67 @@
+// Package concurrency holds building blocks for running work on goroutines
+// without sleeping and hoping: worker pools, pipeline stages, error groups
+// and rate limiting.
+package concurrency
+
+import (
+	"context"
+	"sync"
+)
+
+// Group runs functions on goroutines and waits for them. The first error
+// cancels the group's context, so the other functions can stop early.
+// It works like golang.org/x/sync/errgroup.
+type Group struct {
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	sem     chan struct{}
+	errOnce sync.Once
+	err     error
+}
+
+// WithContext returns a group and a context that's cancelled when a
+// function fails or Wait returns.
+func WithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Group{cancel: cancel}, ctx
+}
+
+// SetLimit caps the number of functions running at once, Go blocks when
+// the limit is reached. It must be called before the first Go.
+func (g *Group) SetLimit(n int) {
+	if n <= 0 {
+		g.sem = nil
+		return
+	}
+	g.sem = make(chan struct{}, n)
+}
+
+func (g *Group) Go(fn func() error) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if g.sem != nil {
+			defer func() { <-g.sem }()
+		}
+		if err := fn(); err != nil {
+			g.errOnce.Do(func() {
+				g.err = err
+				if g.cancel != nil {
+					g.cancel()
+				}
+			})
+		}
+	}()
+}
+
+// Wait blocks until every function returned and gives back the first error.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel()
+	}
+	return g.err
+}
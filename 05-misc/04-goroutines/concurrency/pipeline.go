@@ -0,0 +1,99 @@
+package concurrency
+
+import (
+	"context"
+	"sync"
+)
+
+// Generate sends values on a channel that's closed after the last one,
+// or as soon as ctx is done.
+func Generate[T any](ctx context.Context, values ...T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for _, v := range values {
+			select {
+			case out <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Stage runs fn on every value from in using workers goroutines started on g.
+// The output channel is closed when in is drained. Values may come out in a
+// different order than they went in. An error from fn fails the group, which
+// cancels ctx when it came from WithContext, and the stage stops.
+//
+//	g, ctx := concurrency.WithContext(context.Background())
+//	lines := concurrency.Generate(ctx, files...)
+//	counts := concurrency.Stage(g, ctx, lines, 4, countWords)
+//	for c := range counts { ... }
+//	err := g.Wait()
+func Stage[In, Out any](g *Group, ctx context.Context, in <-chan In, workers int, fn func(context.Context, In) (Out, error)) <-chan Out {
+	if workers <= 0 {
+		workers = 1
+	}
+	out := make(chan Out)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		g.Go(func() error {
+			defer wg.Done()
+			for v := range in {
+				result, err := fn(ctx, v)
+				if err != nil {
+					return err
+				}
+				select {
+				case out <- result:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return nil
+		})
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// FanOut spreads the values from in over n channels, each value goes to one
+// of them. All channels are closed once in is drained or ctx is done.
+func FanOut[T any](ctx context.Context, in <-chan T, n int) []<-chan T {
+	if n <= 0 {
+		n = 1
+	}
+	outs := make([]chan T, n)
+	result := make([]<-chan T, n)
+	for i := range outs {
+		outs[i] = make(chan T)
+		result[i] = outs[i]
+	}
+
+	// each goroutine closes its own channel, so there's nothing to wait for
+	for i := range outs {
+		go func(out chan T) {
+			defer close(out)
+			for v := range in {
+				select {
+				case out <- v:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(outs[i])
+	}
+	return result
+}
+
+// FanIn merges several channels into one, it's another name for Merge.
+func FanIn[T any](ctx context.Context, ins ...<-chan T) <-chan T {
+	return Merge(ctx, ins...)
+}
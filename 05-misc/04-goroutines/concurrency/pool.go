@@ -0,0 +1,162 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+)
+
+var ErrPoolClosed = errors.New("pool is closed")
+
+// Result is the outcome of one task.
+type Result[T, R any] struct {
+	Task  T
+	Value R
+	Err   error
+}
+
+// Results are kept in the order the tasks were submitted.
+type Results[T, R any] []Result[T, R]
+
+// Values returns the values of the tasks that succeeded.
+func (rs Results[T, R]) Values() []R {
+	values := make([]R, 0, len(rs))
+	for _, r := range rs {
+		if r.Err == nil {
+			values = append(values, r.Value)
+		}
+	}
+	return values
+}
+
+// Err returns nil when every task succeeded, otherwise an Errors with all failures.
+func (rs Results[T, R]) Err() error {
+	var errs Errors
+	for _, r := range rs {
+		if r.Err != nil {
+			errs = append(errs, r.Err)
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Errors collects the failures of several tasks.
+type Errors []error
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Is lets errors.Is look at every collected error.
+func (e Errors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Pool runs tasks on a fixed number of workers.
+//
+//	pool := concurrency.NewPool(ctx, 4, process)
+//	for _, file := range files {
+//		pool.Submit(file)
+//	}
+//	results := pool.Wait()
+type Pool[T, R any] struct {
+	ctx     context.Context
+	fn      func(context.Context, T) (R, error)
+	tasks   chan task[T]
+	wg      sync.WaitGroup
+	sending sync.WaitGroup
+	mu      sync.Mutex
+	closed  bool
+	next    int
+	results Results[T, R]
+}
+
+type task[T any] struct {
+	index int
+	value T
+}
+
+// NewPool starts workers goroutines that call fn for every submitted task.
+// When ctx is cancelled, tasks that haven't started fail with ctx.Err().
+func NewPool[T, R any](ctx context.Context, workers int, fn func(context.Context, T) (R, error)) *Pool[T, R] {
+	if workers <= 0 {
+		workers = 1
+	}
+	p := &Pool[T, R]{ctx: ctx, fn: fn, tasks: make(chan task[T])}
+	for i := 0; i < workers; i++ {
+		p.wg.Add(1)
+		go p.work()
+	}
+	return p
+}
+
+func (p *Pool[T, R]) work() {
+	defer p.wg.Done()
+	for t := range p.tasks {
+		var r Result[T, R]
+		r.Task = t.value
+		if err := p.ctx.Err(); err != nil {
+			r.Err = err
+		} else {
+			r.Value, r.Err = p.fn(p.ctx, t.value)
+		}
+		p.mu.Lock()
+		p.results[t.index] = r
+		p.mu.Unlock()
+	}
+}
+
+// Submit queues a task and blocks until a worker takes it. It fails once
+// Wait was called or the context is done.
+func (p *Pool[T, R]) Submit(value T) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return ErrPoolClosed
+	}
+	index := p.next
+	p.next++
+	p.results = append(p.results, Result[T, R]{Task: value})
+	p.sending.Add(1)
+	p.mu.Unlock()
+	defer p.sending.Done()
+
+	select {
+	case p.tasks <- task[T]{index: index, value: value}:
+		return nil
+	case <-p.ctx.Done():
+		p.mu.Lock()
+		p.results[index].Err = p.ctx.Err()
+		p.mu.Unlock()
+		return p.ctx.Err()
+	}
+}
+
+// Wait stops accepting tasks, waits for the running ones and returns all results.
+func (p *Pool[T, R]) Wait() Results[T, R] {
+	p.mu.Lock()
+	closing := !p.closed
+	p.closed = true
+	p.mu.Unlock()
+
+	if closing {
+		// let Submit calls that got in before finish handing over their task
+		p.sending.Wait()
+		close(p.tasks)
+	}
+	p.wg.Wait()
+	return p.results
+}
@@ -1,28 +1,32 @@
+//go:build ignore
+
+// Run with: go run file-search.go
 package main
 
 import (
-	"io/ioutil"
+	"context"
 	"log"
-	"path/filepath"
+	"time"
+
+	"goroutines/search"
 )
 
-func SearchFiles(dir string, lookFor string) string {
-	log.Println("[SEARCHING] ", dir)
-	files, err := ioutil.ReadDir(dir)
+func main() {
+	// give up if the search takes too long
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	glob, err := search.Glob("*.txt")
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	for _, file := range files {
-		log.Println(dir+file.Name(), file.IsDir())
-		if file.Name() == lookFor {
-			return "[FOUND] " + filepath.Join(dir, file.Name())
+	// FirstMatch stops every worker as soon as one of them finds a file
+	for res := range search.Search(ctx, ".", glob, search.Options{Workers: 4, FirstMatch: true}) {
+		if res.Err != nil {
+			log.Println("[ERROR]", res.Err)
+			continue
 		}
+		log.Println("[FOUND]", res.Path)
 	}
-	return "[NOT FOUND] " + dir
-}
-
-func main() {
-	result := make([]string, 0)
-	go append(result, SearchFiles("./test", "test2.txt"))
 }
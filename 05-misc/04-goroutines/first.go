@@ -1,25 +1,35 @@
+//go:build ignore
+
+// Run with: go run first.go
 package main
 
 import (
+	"context"
 	"fmt"
 	"time"
+
+	"goroutines/concurrency"
 )
 
-func myFunction() {
+func myFunction() error {
 	time.Sleep(1500 * time.Millisecond)
 	for i := 0; i < 3; i++ {
 		fmt.Println("my function: ", i)
 	}
+	return nil
 }
-func anotherFunction() {
+func anotherFunction() error {
 	time.Sleep(500 * time.Millisecond)
 	for i := 4; i < 7; i++ {
 		fmt.Println("another function: ", i)
 	}
+	return nil
 }
 
 func main() {
-	go myFunction()
-	go anotherFunction()
-	time.Sleep(2 * time.Second)
+	// wait for both functions instead of sleeping long enough for them
+	g, _ := concurrency.WithContext(context.Background())
+	g.Go(myFunction)
+	g.Go(anotherFunction)
+	g.Wait()
 }
@@ -1,37 +1,25 @@
 package main
 
 import (
-	"io/ioutil"
+	"context"
 	"log"
-	"path/filepath"
+
+	"goroutines/search"
 )
 
-func SearchFiles(dir string, lookFor string, ch chan string) {
-	log.Println("[SEARCHING] ", dir)
-	files, err := ioutil.ReadDir(dir)
-	if err != nil {
-		log.Fatal(err)
-	}
+func main() {
+	results := search.Search(context.Background(), ".", search.Name("test2.txt"), search.Options{})
 
-	for _, file := range files {
-		log.Println(dir+file.Name(), file.IsDir())
-		if file.Name() == lookFor {
-			ch <- "[FOUND] " + filepath.Join(dir, file.Name())
-			return
+	found := 0
+	for res := range results {
+		if res.Err != nil {
+			log.Println("[ERROR]", res.Err)
+			continue
 		}
+		found++
+		log.Println("[FOUND]", res.Path)
 	}
-	ch <- "[NOT FOUND] " + dir
-}
-
-func main() {
-	ch := make(chan string)
-
-	go SearchFiles("./test/", "test2.txt", ch)
-	go SearchFiles("./other/", "test2.txt", ch)
-
-	var res = ""
-	for i := 0; i < 2; i++ {
-		res = <-ch
-		log.Println(res)
+	if found == 0 {
+		log.Println("[NOT FOUND] test2.txt")
 	}
 }
@@ -0,0 +1,220 @@
+package search
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"regexp"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultMaxSize is the largest file Grep reads unless told otherwise.
+const DefaultMaxSize = 10 * 1024 * 1024
+
+// how much of a file is checked for NUL bytes to tell if it's binary
+const sniffLen = 8000
+
+type GrepOptions struct {
+	Pattern    string
+	Regex      bool // Pattern is a regular expression, not literal text
+	IgnoreCase bool
+	Context    int     // lines to include before and after each match
+	MaxSize    int64   // skip larger files, 0 means DefaultMaxSize and -1 no limit, see Grep for long lines
+	Files      Matcher // only grep the files it matches, nil greps all files
+	Workers    int     // files read at the same time, defaults to the number of CPUs
+	FirstMatch bool    // stop after the first matching line
+	NoIgnore   bool    // grep files excluded by .gitignore too
+}
+
+// Match is a matching line, or an error for a file that couldn't be read.
+// Line and Column start at 1, Column counts bytes.
+type Match struct {
+	Path   string   `json:"path"`
+	Line   int      `json:"line,omitempty"`
+	Column int      `json:"column,omitempty"`
+	Text   string   `json:"text,omitempty"`
+	Before []string `json:"before,omitempty"`
+	After  []string `json:"after,omitempty"`
+	Err    error    `json:"-"`
+}
+
+// Grep searches the contents of the files below root and streams every
+// matching line. Binary files and files above MaxSize are skipped.
+// Without a MaxSize, lines are still capped at DefaultMaxSize, so a huge
+// file without line breaks can't take all the memory. A longer line is sent
+// as a bufio.ErrTooLong error for its file.
+// It only fails up front, when the pattern doesn't compile.
+func Grep(ctx context.Context, root string, opts GrepOptions) (<-chan Match, error) {
+	expr := opts.Pattern
+	if !opts.Regex {
+		expr = regexp.QuoteMeta(expr)
+	}
+	if opts.IgnoreCase {
+		expr = "(?i)" + expr
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("search: bad pattern: %w", err)
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	files := opts.Files
+	if files == nil {
+		files = MatcherFunc(func(string, fs.DirEntry) bool { return true })
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	g := &grepper{ctx: ctx, cancel: cancel, re: re, opts: opts, out: make(chan Match)}
+	paths := Search(ctx, root, files, Options{Workers: workers, GitIgnore: !opts.NoIgnore})
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for res := range paths {
+				if res.Err != nil {
+					g.send(Match{Path: res.Path, Err: res.Err})
+					continue
+				}
+				if err := g.grepFile(res.Path); err != nil {
+					g.send(Match{Path: res.Path, Err: err})
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		cancel()
+		close(g.out)
+	}()
+	return g.out, nil
+}
+
+type grepper struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	re      *regexp.Regexp
+	opts    GrepOptions
+	out     chan Match
+	matched int32
+}
+
+func (g *grepper) send(m Match) bool {
+	if g.opts.FirstMatch && m.Err == nil {
+		if !atomic.CompareAndSwapInt32(&g.matched, 0, 1) {
+			return false
+		}
+		defer g.cancel()
+	}
+	select {
+	case g.out <- m:
+		return true
+	case <-g.ctx.Done():
+		return false
+	}
+}
+
+func (g *grepper) grepFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	maxSize := g.opts.MaxSize
+	if maxSize == 0 {
+		maxSize = DefaultMaxSize
+	}
+	if maxSize > 0 && info.Size() > maxSize {
+		return nil
+	}
+
+	r := bufio.NewReader(f)
+	head, err := r.Peek(sniffLen)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return err
+	}
+	if bytes.IndexByte(head, 0) >= 0 {
+		return nil
+	}
+
+	return g.grep(path, r)
+}
+
+// grep scans r line by line. Matches wait in pending until their After lines are read.
+func (g *grepper) grep(path string, r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	maxLine := int(g.opts.MaxSize)
+	if maxLine < bufio.MaxScanTokenSize {
+		maxLine = DefaultMaxSize
+	}
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLine)
+
+	n := g.opts.Context
+	var before []string
+	var pending []*Match
+	flush := func(all bool) bool {
+		for len(pending) > 0 && (all || len(pending[0].After) >= n) {
+			if !g.send(*pending[0]) {
+				return false
+			}
+			pending = pending[1:]
+		}
+		return true
+	}
+
+	line := 0
+	for scanner.Scan() {
+		if g.ctx.Err() != nil {
+			return nil
+		}
+		line++
+		text := scanner.Text()
+
+		for _, m := range pending {
+			if len(m.After) < n {
+				m.After = append(m.After, text)
+			}
+		}
+		if !flush(false) {
+			return nil
+		}
+
+		if loc := g.re.FindStringIndex(text); loc != nil {
+			m := &Match{Path: path, Line: line, Column: loc[0] + 1, Text: text}
+			if len(before) > 0 {
+				m.Before = append([]string(nil), before...)
+			}
+			pending = append(pending, m)
+			if !flush(false) {
+				return nil
+			}
+		}
+
+		if n > 0 {
+			before = append(before, text)
+			if len(before) > n {
+				before = before[1:]
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	flush(true)
+	return nil
+}
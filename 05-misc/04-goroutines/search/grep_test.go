@@ -0,0 +1,110 @@
+package search
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func grepAll(t *testing.T, root string, opts GrepOptions) []Match {
+	matches, err := Grep(context.Background(), root, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var all []Match
+	for m := range matches {
+		if m.Err != nil {
+			t.Fatal(m.Err)
+		}
+		m.Path, _ = filepath.Rel(root, m.Path)
+		m.Path = filepath.ToSlash(m.Path)
+		all = append(all, m)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Path != all[j].Path {
+			return all[i].Path < all[j].Path
+		}
+		return all[i].Line < all[j].Line
+	})
+	return all
+}
+
+func write(t *testing.T, root, name, content string) {
+	path := filepath.Join(root, filepath.FromSlash(name))
+	os.MkdirAll(filepath.Dir(path), 0755)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGrep(t *testing.T) {
+	root := t.TempDir()
+	write(t, root, "a.txt", "one\ntwo needle\nthree\nfour\nNeedle five\n")
+	write(t, root, "sub/b.go", "package b\n// a.b literal\n")
+	write(t, root, "bin.dat", "needle\x00\x01")
+
+	matches := grepAll(t, root, GrepOptions{Pattern: "needle", IgnoreCase: true, Context: 1})
+	expected := []Match{
+		{Path: "a.txt", Line: 2, Column: 5, Text: "two needle", Before: []string{"one"}, After: []string{"three"}},
+		{Path: "a.txt", Line: 5, Column: 1, Text: "Needle five", Before: []string{"four"}},
+	}
+	if !reflect.DeepEqual(matches, expected) {
+		t.Errorf("Actual: %+v, Expected: %+v", matches, expected)
+	}
+
+	// literal patterns don't treat the dot as a wildcard
+	matches = grepAll(t, root, GrepOptions{Pattern: "a.b"})
+	if len(matches) != 1 || matches[0].Path != "sub/b.go" {
+		t.Errorf("Literal grep was incorrect, Actual: %+v", matches)
+	}
+	matches = grepAll(t, root, GrepOptions{Pattern: `^t\w+`, Regex: true})
+	if len(matches) != 2 || matches[0].Text != "two needle" || matches[1].Text != "three" {
+		t.Errorf("Regex grep was incorrect, Actual: %+v", matches)
+	}
+
+	glob, _ := Glob("*.go")
+	matches = grepAll(t, root, GrepOptions{Pattern: "package", Files: glob})
+	if len(matches) != 1 {
+		t.Errorf("Files filter was ignored, Actual: %+v", matches)
+	}
+	matches = grepAll(t, root, GrepOptions{Pattern: "one", MaxSize: 10})
+	if len(matches) != 0 {
+		t.Errorf("MaxSize was ignored, Actual: %+v", matches)
+	}
+	matches = grepAll(t, root, GrepOptions{Pattern: "e", FirstMatch: true})
+	if len(matches) != 1 {
+		t.Errorf("FirstMatch should return one match, Actual: %+v", matches)
+	}
+
+	if _, err := Grep(context.Background(), root, GrepOptions{Pattern: "(", Regex: true}); err == nil {
+		t.Error("Grep should reject a bad regex")
+	}
+}
+
+func TestGitIgnore(t *testing.T) {
+	root := t.TempDir()
+	write(t, root, ".gitignore", "# build output\n*.log\n/build/\n!keep.log\ndocs/**/draft.md\n")
+	write(t, root, "app.log", "x")
+	write(t, root, "keep.log", "x")
+	write(t, root, "build/out.txt", "x")
+	write(t, root, "src/build/gen.txt", "x")
+	write(t, root, "docs/a/b/draft.md", "x")
+	write(t, root, "docs/draft.md", "x")
+	write(t, root, "src/.gitignore", "gen.txt\n")
+	write(t, root, "src/main.txt", "x")
+	write(t, root, ".git/config", "x")
+
+	all := MatcherFunc(func(string, os.DirEntry) bool { return true })
+	paths, err := Find(context.Background(), root, all, Options{GitIgnore: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// "docs/**/draft.md" matches docs/draft.md too, like in git
+	expected := []string{".gitignore", "keep.log", "src/.gitignore", "src/main.txt"}
+	if got := rel(t, root, paths); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Actual: %v, Expected: %v", got, expected)
+	}
+}
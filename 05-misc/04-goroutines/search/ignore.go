@@ -0,0 +1,163 @@
+package search
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// ignoreRule is one line of a .gitignore file.
+type ignoreRule struct {
+	base    string // directory of the .gitignore, relative to the root, "" for the root
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// ignoreList holds the rules that apply to a directory, from the root down.
+// Lists are never changed once built, so workers can share them.
+type ignoreList struct {
+	rules []ignoreRule
+}
+
+// loadIgnore returns parent extended with the .gitignore in dir, if there is one.
+func loadIgnore(parent *ignoreList, dir string, base string) *ignoreList {
+	f, err := os.Open(dir + string(os.PathSeparator) + ".gitignore")
+	if err != nil {
+		return parent
+	}
+	defer f.Close()
+
+	var rules []ignoreRule
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if rule, ok := parseIgnoreLine(scanner.Text(), base); ok {
+			rules = append(rules, rule)
+		}
+	}
+	if len(rules) == 0 {
+		return parent
+	}
+
+	list := &ignoreList{}
+	if parent != nil {
+		list.rules = append(list.rules, parent.rules...)
+	}
+	list.rules = append(list.rules, rules...)
+	return list
+}
+
+func parseIgnoreLine(line string, base string) (ignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+
+	rule := ignoreRule{base: base}
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\`) {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return ignoreRule{}, false
+	}
+
+	// a slash at the start or in the middle ties the pattern to the .gitignore directory
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	expr := globToRegexp(line)
+	if anchored || strings.HasPrefix(line, "**/") {
+		expr = "^" + expr + "$"
+	} else {
+		expr = "(?:^|/)" + expr + "$"
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return ignoreRule{}, false
+	}
+	rule.re = re
+	return rule, true
+}
+
+// globToRegexp translates gitignore wildcards, including **, to a regular expression.
+func globToRegexp(glob string) string {
+	var sb strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			sb.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "/**") && i+3 == len(glob):
+			sb.WriteString("/.*")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				sb.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + class + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(glob):
+			i++
+			sb.WriteString(regexp.QuoteMeta(string(glob[i])))
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return sb.String()
+}
+
+// ignored reports whether rel, a slash separated path relative to the
+// search root, is excluded. Like git, the last matching rule wins.
+func (l *ignoreList) ignored(rel string, isDir bool) bool {
+	if l == nil {
+		return false
+	}
+	ignored := false
+	for _, rule := range l.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		p := rel
+		if rule.base != "" {
+			if !strings.HasPrefix(rel, rule.base+"/") {
+				continue
+			}
+			p = rel[len(rule.base)+1:]
+		}
+		if rule.re.MatchString(p) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// joinRel joins slash separated relative paths, where "" is the root.
+func joinRel(dir, name string) string {
+	if dir == "" {
+		return name
+	}
+	return path.Join(dir, name)
+}
@@ -0,0 +1,70 @@
+package search
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Matcher decides if an entry is a hit. rel is the entry's path relative
+// to the search root, using forward slashes.
+type Matcher interface {
+	Match(rel string, d fs.DirEntry) bool
+}
+
+// MatcherFunc turns a function into a Matcher.
+type MatcherFunc func(rel string, d fs.DirEntry) bool
+
+func (f MatcherFunc) Match(rel string, d fs.DirEntry) bool {
+	return f(rel, d)
+}
+
+// Name matches entries called exactly name.
+func Name(name string) Matcher {
+	return MatcherFunc(func(rel string, d fs.DirEntry) bool {
+		return d.Name() == name
+	})
+}
+
+// Glob matches the entry name against a shell pattern like "*.txt".
+// A pattern containing a slash, like "test/*.txt", is matched against the relative path.
+func Glob(pattern string) (Matcher, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("search: bad glob %q: %w", pattern, err)
+	}
+	full := strings.Contains(pattern, "/")
+	return MatcherFunc(func(rel string, d fs.DirEntry) bool {
+		target := d.Name()
+		if full {
+			target = rel
+		}
+		ok, _ := path.Match(pattern, target)
+		return ok
+	}), nil
+}
+
+// Regex matches the relative path against a regular expression,
+// use anchors like `(^|/)test\d\.txt$` to match whole names.
+func Regex(expr string) (Matcher, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("search: bad regex: %w", err)
+	}
+	return MatcherFunc(func(rel string, d fs.DirEntry) bool {
+		return re.MatchString(rel)
+	}), nil
+}
+
+// All matches entries that every one of matchers matches.
+func All(matchers ...Matcher) Matcher {
+	return MatcherFunc(func(rel string, d fs.DirEntry) bool {
+		for _, m := range matchers {
+			if !m.Match(rel, d) {
+				return false
+			}
+		}
+		return true
+	})
+}
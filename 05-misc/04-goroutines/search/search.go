@@ -0,0 +1,191 @@
+// Package search walks directory trees concurrently and streams the
+// entries that match over a channel.
+package search
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Result is either a match, or an error for a directory that couldn't be read.
+type Result struct {
+	Path  string
+	IsDir bool
+	Err   error
+}
+
+type Options struct {
+	Workers     int  // directories read at the same time, defaults to the number of CPUs
+	FirstMatch  bool // stop after the first match
+	IncludeDirs bool // let directories match too, not only files
+	GitIgnore   bool // skip .git and whatever .gitignore files exclude
+}
+
+// Search walks root with a bounded pool of workers and sends every match on the
+// returned channel. The channel is closed when the walk is done, when the first
+// match was found with FirstMatch, or when ctx is cancelled.
+//
+// Errors are sent as results, the walk continues past directories it can't read.
+func Search(ctx context.Context, root string, m Matcher, opts Options) <-chan Result {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	out := make(chan Result)
+	ctx, cancel := context.WithCancel(ctx)
+	s := &searcher{ctx: ctx, cancel: cancel, root: root, matcher: m, opts: opts, out: out}
+
+	go func() {
+		defer close(out)
+		defer cancel()
+		s.run(workers)
+	}()
+	return out
+}
+
+type searcher struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	root    string
+	matcher Matcher
+	opts    Options
+	out     chan<- Result
+	matched int32
+}
+
+// dirJob is a directory waiting to be read, along with the ignore rules above it.
+type dirJob struct {
+	path   string
+	rel    string
+	ignore *ignoreList
+}
+
+// run hands directories to the workers. Workers send back the directories they
+// find below, so the queue lives here and no worker ever blocks another one.
+func (s *searcher) run(workers int) {
+	jobs := make(chan dirJob)
+	found := make(chan []dirJob)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				found <- s.scan(job)
+			}
+		}()
+	}
+
+	queue := []dirJob{{path: s.root}}
+	pending := 0
+	done := s.ctx.Done()
+	for len(queue) > 0 || pending > 0 {
+		var send chan<- dirJob
+		var next dirJob
+		if len(queue) > 0 {
+			send = jobs
+			next = queue[len(queue)-1]
+		}
+
+		select {
+		case send <- next:
+			queue = queue[:len(queue)-1]
+			pending++
+		case dirs := <-found:
+			pending--
+			queue = append(queue, dirs...)
+		case <-done:
+			// stop handing out work, but let the busy workers finish
+			done = nil
+		}
+		if done == nil {
+			queue = nil
+		}
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// scan reads one directory, sends its matches and returns its subdirectories.
+func (s *searcher) scan(job dirJob) []dirJob {
+	if s.ctx.Err() != nil {
+		return nil
+	}
+	entries, err := os.ReadDir(job.path)
+	if err != nil {
+		s.send(Result{Path: job.path, IsDir: true, Err: err})
+		return nil
+	}
+
+	ignore := job.ignore
+	if s.opts.GitIgnore {
+		ignore = loadIgnore(ignore, job.path, job.rel)
+	}
+
+	var dirs []dirJob
+	for _, entry := range entries {
+		if s.ctx.Err() != nil {
+			return nil
+		}
+		path := filepath.Join(job.path, entry.Name())
+		rel := joinRel(job.rel, entry.Name())
+		if s.opts.GitIgnore && (entry.Name() == ".git" || ignore.ignored(rel, entry.IsDir())) {
+			continue
+		}
+		if entry.IsDir() {
+			dirs = append(dirs, dirJob{path: path, rel: rel, ignore: ignore})
+			if !s.opts.IncludeDirs {
+				continue
+			}
+		}
+
+		if !s.matcher.Match(rel, entry) {
+			continue
+		}
+		if s.opts.FirstMatch {
+			if !atomic.CompareAndSwapInt32(&s.matched, 0, 1) {
+				return nil
+			}
+			s.send(Result{Path: path, IsDir: entry.IsDir()})
+			s.cancel()
+			return nil
+		}
+		s.send(Result{Path: path, IsDir: entry.IsDir()})
+	}
+	return dirs
+}
+
+func (s *searcher) send(r Result) {
+	select {
+	case s.out <- r:
+	case <-s.ctx.Done():
+	}
+}
+
+// Find collects the matches of Search, sorted by path. It returns the
+// first error it meets together with everything that was found.
+func Find(ctx context.Context, root string, m Matcher, opts Options) ([]string, error) {
+	var paths []string
+	var firstErr error
+	for r := range Search(ctx, root, m, opts) {
+		if r.Err != nil {
+			if firstErr == nil {
+				firstErr = r.Err
+			}
+			continue
+		}
+		paths = append(paths, r.Path)
+	}
+	sort.Strings(paths)
+	if firstErr == nil {
+		firstErr = ctx.Err()
+	}
+	return paths, firstErr
+}
@@ -0,0 +1,119 @@
+package search
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// tree creates files below a temporary directory and returns it.
+func tree(t *testing.T, files ...string) string {
+	root := t.TempDir()
+	for _, f := range files {
+		path := filepath.Join(root, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(f), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func rel(t *testing.T, root string, paths []string) []string {
+	var out []string
+	for _, p := range paths {
+		r, err := filepath.Rel(root, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, filepath.ToSlash(r))
+	}
+	return out
+}
+
+func TestFind(t *testing.T) {
+	root := tree(t, "a.txt", "test/test2.txt", "test/deep/er/test2.txt", "other/test2.go", "other/b.txt")
+
+	glob, _ := Glob("*.txt")
+	pathGlob, _ := Glob("test/*.txt")
+	regex, _ := Regex(`(^|/)test\d\.`)
+	tests := []struct {
+		name    string
+		matcher Matcher
+		want    []string
+	}{
+		{"name", Name("test2.txt"), []string{"test/deep/er/test2.txt", "test/test2.txt"}},
+		{"glob", glob, []string{"a.txt", "other/b.txt", "test/deep/er/test2.txt", "test/test2.txt"}},
+		{"path glob", pathGlob, []string{"test/test2.txt"}},
+		{"regex", regex, []string{"other/test2.go", "test/deep/er/test2.txt", "test/test2.txt"}},
+	}
+	for _, test := range tests {
+		for _, workers := range []int{1, 4} {
+			paths, err := Find(context.Background(), root, test.matcher, Options{Workers: workers})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := rel(t, root, paths); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("%s with %d workers, Actual: %v, Expected: %v", test.name, workers, got, test.want)
+			}
+		}
+	}
+}
+
+func TestIncludeDirs(t *testing.T) {
+	root := tree(t, "test/a.txt", "other/test/b.txt")
+	paths, err := Find(context.Background(), root, Name("test"), Options{IncludeDirs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := rel(t, root, paths); !reflect.DeepEqual(got, []string{"other/test", "test"}) {
+		t.Errorf("Actual: %v, Expected: [other/test test]", got)
+	}
+}
+
+func TestFirstMatch(t *testing.T) {
+	var files []string
+	for _, dir := range []string{"a", "b", "c", "d"} {
+		for _, sub := range []string{"x", "y", "z"} {
+			files = append(files, dir+"/"+sub+"/hit.txt")
+		}
+	}
+	root := tree(t, files...)
+
+	for i := 0; i < 20; i++ {
+		paths, err := Find(context.Background(), root, Name("hit.txt"), Options{Workers: 4, FirstMatch: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(paths) != 1 {
+			t.Fatalf("FirstMatch should return one path, Actual: %v", paths)
+		}
+	}
+}
+
+func TestCancel(t *testing.T) {
+	root := tree(t, "a/b/c.txt")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := Find(ctx, root, Name("c.txt"), Options{})
+	if err != context.Canceled {
+		t.Errorf("Actual: %v, Expected: %v", err, context.Canceled)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	_, err := Find(context.Background(), filepath.Join(t.TempDir(), "missing"), Name("x"), Options{})
+	if !os.IsNotExist(err) {
+		t.Errorf("Expected a not exist error, Actual: %v", err)
+	}
+	if _, err := Glob("[a"); err == nil {
+		t.Error("Glob should reject a bad pattern")
+	}
+	if _, err := Regex("(a"); err == nil {
+		t.Error("Regex should reject a bad expression")
+	}
+}
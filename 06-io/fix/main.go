@@ -10,12 +10,12 @@ import (
 )
 
 type Products struct {
-	Products []Product `json: products`
+	Products []Product `json:"products,omitempty"`
 }
 
 type Product struct {
-	Id   int    `json: "id"`
-	Name string `json: "name"`
+	Id   int    `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
 }
 
 // writes JSON to file